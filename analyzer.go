@@ -30,28 +30,120 @@ import (
 )
 
 // Analyzer implements the requiredfield linter.
+// It's configured with command line flags.
 //
 // See package documentation for details.
-var Analyzer = new(requiredfieldLinter).Analyzer()
+// Use New to build an analyzer that's configured programmatically.
+var Analyzer = New(Options{})
+
+// Options configures an analyzer built with New.
+type Options struct {
+	// Name of the analyzer.
+	// Defaults to "requiredfield".
+	//
+	// Analyzers that run side-by-side in the same driver
+	// must have different names.
+	Name string
+
+	// Config specifies fields that are required
+	// in addition to those marked with "// required" comments.
+	//
+	// The configuration is copied:
+	// changes made to it after New returns do not affect the analyzer.
+	Config *Config
+
+	// Promote reports optional fields with a fix
+	// to mark them as required.
+	// This is the same as the -promote flag.
+	Promote bool
+
+	// Conversions reports conversions to struct types
+	// from types that don't require the same fields.
+	// This is the same as the -conversions flag.
+	Conversions bool
+
+	// Strict reports zero values of struct types with required fields
+	// created by make and arrays.
+	// This is the same as the -strict flag.
+	Strict bool
+
+	// Partial allows functions to return values
+	// that are missing required fields
+	// if their callers set those fields before using them.
+	// This is the same as the -partial flag.
+	Partial bool
+}
+
+// New builds a new requiredfield analyzer with the given options.
+//
+// The analyzer also accepts the same flags as Analyzer,
+// which add to the configuration in Options.
+func New(opts Options) *analysis.Analyzer {
+	l := requiredfieldLinter{
+		Promote:     opts.Promote,
+		Conversions: opts.Conversions,
+		Strict:      opts.Strict,
+		Partial:     opts.Partial,
+	}
+	if opts.Config != nil {
+		l.Config = opts.Config.rc.clone()
+	}
+
+	a := l.Analyzer()
+	if opts.Name != "" {
+		a.Name = opts.Name
+	}
+	return a
+}
 
 type requiredfieldLinter struct {
 	Config requiredConfig
+
+	// Promote enables diagnostics on optional fields
+	// with fixes to mark them as required.
+	Promote bool
+
+	// Conversions enables checks on conversions to struct types
+	// from types that don't require the same fields.
+	Conversions bool
+
+	// Strict enables checks on zero values of struct types
+	// with required fields created by make and arrays.
+	Strict bool
+
+	// Partial enables tracking of values missing required fields
+	// returned by functions.
+	Partial bool
 }
 
 func (l *requiredfieldLinter) Analyzer() *analysis.Analyzer {
 	a := &analysis.Analyzer{
-		Name: "requiredfield",
-		Doc:  "check for required fields during struct initialization",
-		Run:  l.run,
+		Name:       "requiredfield",
+		Doc:        "check for required fields during struct initialization",
+		Run:        l.run,
+		ResultType: _resultType,
 		Requires: []*analysis.Analyzer{
 			inspect.Analyzer,
 		},
 		FactTypes: []analysis.Fact{
 			new(isRequiredField),
 			new(hasRequiredFields),
+			new(partialResult),
+			new(isOption),
+			new(requiresOptions),
+			new(requiresCalls),
+			new(requiresParamFields),
 		},
 	}
 	l.Config.RegisterFlags(&a.Flags)
+	a.Flags.BoolVar(&l.Promote, "promote", l.Promote,
+		"report optional fields of struct types with a fix to mark them as required; intended for editors")
+	a.Flags.BoolVar(&l.Conversions, "conversions", l.Conversions,
+		"report conversions to struct types from types that don't require the same fields")
+	a.Flags.BoolVar(&l.Strict, "strict", l.Strict,
+		"report zero values of struct types with required fields created by make and arrays")
+	a.Flags.BoolVar(&l.Partial, "partial", l.Partial,
+		"allow functions to return values missing required fields if callers set them before use")
 	return a
 }
 
@@ -63,14 +155,42 @@ func (l *requiredfieldLinter) run(pass *analysis.Pass) (any, error) {
 		Info:             pass.TypesInfo,
 		ExportObjectFact: pass.ExportObjectFact,
 		Reportf:          pass.Reportf,
+		Config:           &l.Config,
 	}).Find(inspect)
 
-	(&enforcer{
-		Info:             pass.TypesInfo,
-		ImportObjectFact: pass.ImportObjectFact,
-		Reportf:          pass.Reportf,
-		Config:           &l.Config,
-	}).Enforce(inspect)
+	if !l.Config.IsExempt(pass.Pkg.Path()) {
+		(&enforcer{
+			Info:             pass.TypesInfo,
+			PkgPath:          pass.Pkg.Path(),
+			ImportObjectFact: pass.ImportObjectFact,
+			Report:           pass.Report,
+			Config:           &l.Config,
+			Conversions:      l.Conversions,
+			Strict:           l.Strict,
+			Partial:          l.Partial,
+			ExportObjectFact: pass.ExportObjectFact,
+		}).Enforce(inspect)
+	}
+
+	if l.Promote {
+		(&promoter{
+			Fset:   pass.Fset,
+			Info:   pass.TypesInfo,
+			Pkg:    pass.Pkg,
+			Report: pass.Report,
+			Config: &l.Config,
+		}).Promote(inspect)
+	}
 
-	return nil, nil
+	result := (&lister{
+		Fset:    pass.Fset,
+		Info:    pass.TypesInfo,
+		PkgPath: pass.Pkg.Path(),
+		Config:  &l.Config,
+		Report:  pass.Report,
+	}).List(pass.Files)
+	result.pkgPath = pass.Pkg.Path()
+	result.importFact = pass.ImportObjectFact
+	result.config = &l.Config
+	return result, nil
 }
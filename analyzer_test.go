@@ -1,14 +1,27 @@
 package requiredfield
 
 import (
+	"errors"
+	"go/types"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
 )
 
+// _testFlags holds flags for the analyzer
+// for packages in testdata/src that need them.
+var _testFlags = map[string][]string{
+	"conversions": {"-conversions"},
+	"partial":     {"-partial"},
+	"promote":     {"-promote"},
+	"strict":      {"-strict"},
+}
+
 func TestAnalyzer(t *testing.T) {
 	testDataDir := analysistest.TestData()
 	srcDir := filepath.Join(testDataDir, "src")
@@ -32,15 +45,204 @@ func TestAnalyzer(t *testing.T) {
 
 			var linter requiredfieldLinter
 
-			rcPath := filepath.Join(srcDir, pkg, "requiredfield.rc")
-			if bs, err := os.ReadFile(rcPath); err == nil {
-				err := linter.Config.Parse(strings.NewReader(string(bs)))
-				if err != nil {
-					t.Fatalf("failed to parse requiredfield.rc: %v", err)
+			for _, name := range []string{"requiredfield.rc", "requiredfield.json"} {
+				path := filepath.Join(srcDir, pkg, name)
+				if _, err := os.Stat(path); err != nil {
+					continue
+				}
+
+				if err := linter.Config.LoadFile(path); err != nil {
+					t.Fatalf("failed to load %v: %v", name, err)
 				}
 			}
 
-			analysistest.Run(t, testDataDir, linter.Analyzer(), pkg)
+			analyzer := linter.Analyzer()
+			if err := analyzer.Flags.Parse(_testFlags[pkg]); err != nil {
+				t.Fatalf("failed to parse flags: %v", err)
+			}
+
+			analysistest.RunWithSuggestedFixes(t, testDataDir, analyzer, pkg+"/...")
 		})
 	}
 }
+
+func TestAnalyzer_categories(t *testing.T) {
+	testDataDir := analysistest.TestData()
+
+	var linter requiredfieldLinter
+	rcPath := filepath.Join(testDataDir, "src", "severity_from_config", "requiredfield.rc")
+	if err := linter.Config.LoadFile(rcPath); err != nil {
+		t.Fatalf("failed to load requiredfield.rc: %v", err)
+	}
+
+	results := analysistest.Run(t, testDataDir, linter.Analyzer(), "severity_from_config")
+
+	wantCategories := map[string]string{
+		"missing required fields: ID":                                                          categoryMissingRequired,
+		"missing required fields: APIKey":                                                      categoryMissingRequired,
+		"warning: missing required fields: Name":                                               categoryMissingRequired,
+		"info: missing required fields: Email":                                                 "rollout",
+		"configured required field Timeout does not exist in severity_from_config.Settings":    categoryConfigError,
+		"configured required field Min does not exist in severity_from_config.Settings.Limits": categoryConfigError,
+	}
+	for _, result := range results {
+		for _, diag := range result.Diagnostics {
+			want, ok := wantCategories[diag.Message]
+			if !ok {
+				t.Errorf("unexpected diagnostic %q", diag.Message)
+				continue
+			}
+			if diag.Category != want {
+				t.Errorf("diagnostic %q: category = %q, want %q", diag.Message, diag.Category, want)
+			}
+		}
+	}
+}
+
+func TestAnalyzer_result(t *testing.T) {
+	testDataDir := analysistest.TestData()
+
+	var linter requiredfieldLinter
+	if err := linter.Config.Parse(strings.NewReader(joinLines(
+		"required external.Server.Name",
+		"required external.Server.Listeners.Limits.MaxConns",
+		"required external.Page[int].Next",
+	))); err != nil {
+		t.Fatalf("failed to parse configuration: %v", err)
+	}
+
+	type field struct{ Type, Name, Description, Origin string }
+	want := map[string][]field{
+		"d": {
+			{"d.Foo", "X", "", ""},
+			{"d.Foo", "Z", "has field tag", ""},
+			{"d.Handler", "Callback", "", ""},
+			{"d.irregularSpacing", "A", "", ""},
+			{"d.irregularSpacing", "B", "", ""},
+			{"d.irregularSpacing", "C", "", ""},
+			{"d.irregularSpacing", "D", "some context", ""},
+			{"d.irregularSpacing", "E", "some context", ""},
+			{"d.invalidTags", "A", "", ""},
+		},
+		"external": {
+			{"external.Server", "Name", "", "1"},
+			{"external.Server.Listeners.Limits", "MaxConns", "", "2"},
+			{"external.Page[int]", "Next", "", "3"},
+		},
+	}
+
+	results := analysistest.Run(t, testDataDir, linter.Analyzer(), "d", "external")
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for _, result := range results {
+		pkg := result.Action.Package.Types.Path()
+		got := make([]field, 0) // non-nil for comparison
+		for _, f := range result.Result.(*RequiredFields).Fields {
+			if !f.Pos.IsValid() {
+				t.Errorf("%v.%v: position is not valid", f.Type, f.Name)
+			}
+			got = append(got, field{f.Type, f.Name, f.Description, f.Origin})
+		}
+
+		if !reflect.DeepEqual(got, want[pkg]) {
+			t.Errorf("package %v: got fields %v, want %v", pkg, got, want[pkg])
+		}
+	}
+}
+
+func TestRequiredFields(t *testing.T) {
+	testDataDir := analysistest.TestData()
+
+	var linter requiredfieldLinter
+	rcPath := filepath.Join(testDataDir, "src", "result", "requiredfield.rc")
+	if err := linter.Config.LoadFile(rcPath); err != nil {
+		t.Fatalf("failed to load requiredfield.rc: %v", err)
+	}
+	requiredAnalyzer := linter.Analyzer()
+
+	// companion reports required fields of the types of
+	// package-level variables by querying the result.
+	companion := &analysis.Analyzer{
+		Name:       "companion",
+		Doc:        "queries required fields",
+		Requires:   []*analysis.Analyzer{requiredAnalyzer},
+		ResultType: reflect.TypeFor[map[string][]string](),
+		Run: func(pass *analysis.Pass) (any, error) {
+			fields := pass.ResultOf[requiredAnalyzer].(*RequiredFields)
+
+			got := make(map[string][]string)
+			for id, obj := range pass.TypesInfo.Defs {
+				if v, ok := obj.(*types.Var); ok && v.Parent() == pass.Pkg.Scope() {
+					got[id.Name] = fields.Required(v.Type())
+				}
+			}
+
+			if !fields.IsRequired(pass.Pkg.Scope().Lookup("user").Type(), "ID") {
+				t.Errorf("IsRequired(external.User, ID) = false, want true")
+			}
+			if fields.IsRequired(pass.Pkg.Scope().Lookup("user").Type(), "Name") {
+				t.Errorf("IsRequired(external.User, Name) = true, want false")
+			}
+			return got, nil
+		},
+	}
+
+	results := analysistest.Run(t, testDataDir, companion, "result")
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+
+	want := map[string][]string{
+		"oneRequired":      {"B"},
+		"requiredExported": {"A", "B"},
+		"allOptional":      nil,
+		"embedded":         {"OneRequired"},
+		"anonymous":        {"X"},
+		"user":             {"ID"},
+		"alias":            {"APIKey"},
+		"local":            {"B"},
+		"notStruct":        nil,
+	}
+	if got := results[0].Result; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestNew(t *testing.T) {
+	testDataDir := analysistest.TestData()
+
+	var cfg Config
+	err := errors.Join(
+		cfg.Require("external.User.ID"),
+		cfg.Require("external.User.Name"),
+		cfg.AddRule(Rule{Type: "external.Config", Fields: []string{"APIKey"}}),
+		cfg.Require("fields_from_config.LocalType.B"),
+	)
+	if err != nil {
+		t.Fatalf("failed to configure: %v", err)
+	}
+
+	analyzer := New(Options{Name: "custom", Config: &cfg})
+	if analyzer.Name != "custom" {
+		t.Errorf("Name = %q, want %q", analyzer.Name, "custom")
+	}
+
+	// Changes after New do not affect the analyzer.
+	if err := cfg.Require("external.Config.Timeout"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testDataDir, analyzer, "fields_from_config")
+}
+
+func TestNew_promote(t *testing.T) {
+	analyzer := New(Options{Promote: true})
+	if got := analyzer.Flags.Lookup("promote").Value.String(); got != "true" {
+		t.Errorf("-promote = %v, want true", got)
+	}
+
+	if got := Analyzer.Flags.Lookup("promote").Value.String(); got != "false" {
+		t.Errorf("default -promote = %v, want false", got)
+	}
+}
@@ -0,0 +1,238 @@
+package requiredfield
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// visitBuild checks that a call to a method
+// with a "requires-call" directive, e.g.
+//
+//	//requiredfield:requires-call Method URL
+//	func (b *RequestBuilder) Build() *Request
+//
+// is preceded by calls to each required method on the same builder.
+// Builders are followed through chains of method calls:
+//
+//	NewRequest().Method("GET").URL(u).Build()
+//
+// And through method calls on local variables before the call:
+//
+//	b := NewRequest().Method("GET")
+//	b.URL(u)
+//	req := b.Build()
+//
+// Only calls that always run before the call are counted:
+// calls in the same block, or in blocks that enclose it.
+// Calls inside conditionals or loops that don't contain it don't count.
+//
+// Builders returned by function calls are assumed to be new.
+// Builders held in parameters or variables from enclosing scopes,
+// and builders passed to other functions, are not checked.
+func (e *enforcer) visitBuild(call *ast.CallExpr, stack []ast.Node) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	fn := typeutil.StaticCallee(e.Info, call)
+	if fn == nil {
+		return
+	}
+
+	var requires requiresCalls
+	if !e.ImportObjectFact(fn.Origin(), &requires) {
+		return
+	}
+
+	called := make(map[string]struct{})
+	root := e.methodChain(sel.X, called)
+	if id, ok := root.(*ast.Ident); ok {
+		v, ok := e.Info.Uses[id].(*types.Var)
+		if !ok || !e.builderVarCalls(v, call.Pos(), stack, called) {
+			return
+		}
+	} else if !isNewBuilder(root) {
+		return
+	}
+
+	var missing []string
+	for _, name := range requires.Methods {
+		if _, ok := called[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	e.Report(analysis.Diagnostic{
+		Pos:      call.Lparen,
+		Category: categoryMissingCall,
+		Message: fmt.Sprintf("missing required calls before %v: %v",
+			fn.Name(), strings.Join(missing, ", ")),
+	})
+}
+
+// methodChain adds the names of methods called in a chain of method calls
+// to called, and returns the expression at the root of the chain.
+// For example, given:
+//
+//	NewRequest().Method("GET").URL(u)
+//
+// It adds Method and URL, and returns NewRequest().
+func (e *enforcer) methodChain(expr ast.Expr, called map[string]struct{}) ast.Expr {
+	for {
+		expr = ast.Unparen(expr)
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return expr
+		}
+		sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok {
+			return expr
+		}
+		if s, ok := e.Info.Selections[sel]; !ok || s.Kind() != types.MethodVal {
+			return expr
+		}
+
+		called[sel.Sel.Name] = struct{}{}
+		expr = sel.X
+	}
+}
+
+// isNewBuilder reports whether the root of a chain of method calls
+// is a new builder: one returned by a function, e.g. NewRequest(),
+// or a composite literal.
+func isNewBuilder(root ast.Expr) bool {
+	if unary, ok := root.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		root = ast.Unparen(unary.X)
+	}
+	switch root.(type) {
+	case *ast.CallExpr, *ast.CompositeLit:
+		return true
+	}
+	return false
+}
+
+// builderVarCalls adds the names of methods called on a builder
+// held in the local variable v before pos to called.
+// stack is the path to the node at pos.
+// Only calls in blocks that enclose pos are added
+// because others may not run before it.
+//
+// It reports false if the builder can't be followed:
+// if v is not declared in the enclosing function,
+// is assigned a builder from elsewhere,
+// or is used other than to call its methods.
+func (e *enforcer) builderVarCalls(v *types.Var, pos token.Pos, stack []ast.Node, called map[string]struct{}) bool {
+	var body *ast.BlockStmt
+	for i := len(stack) - 1; i >= 0 && body == nil; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncDecl:
+			body = n.Body
+		case *ast.FuncLit:
+			body = n.Body
+		}
+	}
+	if body == nil || v.Pos() < body.Pos() || v.Pos() >= body.End() {
+		// Parameters, results, and variables from enclosing scopes.
+		return false
+	}
+
+	// Statement lists that enclose pos.
+	// Statements in them run before pos unconditionally.
+	enclosing := make(map[ast.Node]struct{})
+	for _, n := range stack {
+		switch n.(type) {
+		case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+			enclosing[n] = struct{}{}
+		}
+	}
+
+	// Path to the current node, and the innermost statement list in it.
+	var path []ast.Node
+	innermostBlock := func() ast.Node {
+		for i := len(path) - 1; i >= 0; i-- {
+			switch path[i].(type) {
+			case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+				return path[i]
+			}
+		}
+		return nil
+	}
+
+	// Returns the map that methods called at the current node
+	// should be added to.
+	calledHere := func() map[string]struct{} {
+		if _, ok := enclosing[innermostBlock()]; ok {
+			return called
+		}
+		return make(map[string]struct{}) // may not run before pos
+	}
+
+	// Returns whether a value assigned to v is a builder we can follow:
+	// a new builder, or the same builder after method calls.
+	assign := func(value ast.Expr) bool {
+		root := e.methodChain(value, calledHere())
+		if id, ok := root.(*ast.Ident); ok {
+			return e.Info.Uses[id] == v
+		}
+		return isNewBuilder(root)
+	}
+
+	ok := true
+	ignore := make(map[*ast.Ident]struct{})
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			path = path[:len(path)-1]
+			return false
+		}
+		if !ok || n.Pos() >= pos {
+			return false
+		}
+
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				id, isIdent := ast.Unparen(lhs).(*ast.Ident)
+				if !isIdent || e.Info.ObjectOf(id) != v {
+					continue
+				}
+				ignore[id] = struct{}{}
+				if len(n.Rhs) != len(n.Lhs) || !assign(n.Rhs[i]) {
+					ok = false
+				}
+			}
+
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				if e.Info.Defs[name] == v && i < len(n.Values) && !assign(n.Values[i]) {
+					ok = false
+				}
+			}
+
+		case *ast.CallExpr:
+			chain := make(map[string]struct{})
+			if root, isIdent := e.methodChain(n, chain).(*ast.Ident); isIdent && e.Info.Uses[root] == v {
+				ignore[root] = struct{}{}
+				maps.Copy(calledHere(), chain)
+			}
+
+		case *ast.Ident:
+			if _, ignored := ignore[n]; !ignored && e.Info.Uses[n] == v {
+				// The builder escapes, e.g. configure(b).
+				ok = false
+			}
+		}
+		path = append(path, n)
+		return true
+	})
+	return ok
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"slices"
+	"strings"
+)
+
+// baseline is a list of known diagnostics
+// that should not be reported.
+//
+// Entries are identified by the package, enclosing function,
+// and type of the struct literal, and the fields missing from it,
+// rather than by position
+// so that they survive unrelated edits to the file.
+type baseline struct {
+	Findings []*baselineEntry `json:"findings"`
+}
+
+type baselineEntry struct {
+	// Package is the import path of the package
+	// that contains the struct literal.
+	Package string `json:"package"`
+
+	// Function is the name of the function or method
+	// that contains the struct literal, e.g. "(*Server).Start".
+	// This is empty for literals outside of functions.
+	Function string `json:"function,omitempty"`
+
+	// Type is the fully qualified type of the struct literal.
+	Type string `json:"type,omitempty"`
+
+	// Missing lists required fields missing from the struct literal.
+	Missing []string `json:"missing,omitempty"`
+
+	// Message is the diagnostic message
+	// for diagnostics that are not about missing fields.
+	Message string `json:"message,omitempty"`
+
+	// Count is the number of times this entry occurs
+	// if it occurs more than once.
+	Count int `json:"count,omitempty"`
+}
+
+func (e *baselineEntry) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Package)
+	if e.Function != "" {
+		sb.WriteString(": " + e.Function)
+	}
+	if e.Type != "" {
+		sb.WriteString(": " + e.Type)
+	}
+	if len(e.Missing) > 0 {
+		sb.WriteString(": missing " + strings.Join(e.Missing, ", "))
+	}
+	if e.Message != "" {
+		sb.WriteString(": " + e.Message)
+	}
+	if e.Count > 1 {
+		fmt.Fprintf(&sb, " (x%d)", e.Count)
+	}
+	return sb.String()
+}
+
+// key identifies entries that may match the same finding.
+func (e *baselineEntry) key() baselineKey {
+	return baselineKey{
+		Package:  e.Package,
+		Function: e.Function,
+		Type:     e.Type,
+		Message:  e.Message,
+	}
+}
+
+type baselineKey struct {
+	Package  string
+	Function string
+	Type     string
+	Message  string
+}
+
+func (e *baselineEntry) count() int {
+	return max(e.Count, 1)
+}
+
+// newBaseline builds a baseline from the given findings.
+func newBaseline(findings []*finding) *baseline {
+	counts := make(map[string]*baselineEntry)
+	for _, f := range findings {
+		entry := newBaselineEntry(f)
+
+		// Entries are unique by their JSON representation.
+		bs, err := json.Marshal(entry)
+		if err != nil {
+			panic(err) // unreachable: entries are always serializable
+		}
+		if existing, ok := counts[string(bs)]; ok {
+			existing.Count = existing.count() + 1
+			continue
+		}
+		counts[string(bs)] = entry
+	}
+
+	entries := make([]*baselineEntry, 0, len(counts))
+	for _, e := range counts {
+		entries = append(entries, e)
+	}
+	slices.SortFunc(entries, func(a, b *baselineEntry) int {
+		return cmp.Or(
+			cmp.Compare(a.Package, b.Package),
+			cmp.Compare(a.Function, b.Function),
+			cmp.Compare(a.Type, b.Type),
+			slices.Compare(a.Missing, b.Missing),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+
+	return &baseline{Findings: entries}
+}
+
+const _missingPrefix = "missing required fields: "
+
+// newBaselineEntry builds a baseline entry for a single finding.
+func newBaselineEntry(f *finding) *baselineEntry {
+	entry := baselineEntry{
+		Package: f.Package.PkgPath,
+		Message: f.Diagnostic.Message,
+	}
+
+	lit, decl := f.enclosingNodes()
+	if decl != nil {
+		entry.Function = funcName(f.Package.TypesInfo, decl)
+	}
+	if lit != nil {
+		if typ := f.Package.TypesInfo.TypeOf(lit); typ != nil {
+			entry.Type = types.TypeString(typ, nil)
+		}
+	}
+
+	// Record missing fields instead of the full message
+	// so that findings with fewer missing fields still match.
+	if _, missing, ok := strings.Cut(entry.Message, _missingPrefix); ok {
+		entry.Message = ""
+		entry.Missing = strings.Split(missing, ", ")
+	}
+
+	return &entry
+}
+
+// Match matches findings against the baseline.
+//
+// It returns the IDs of findings that are present in the baseline,
+// and baseline entries that did not match any finding.
+// A finding matches an entry with the same package, function, and type
+// if its missing fields are a subset of the entry's missing fields.
+//
+// Only entries for the given packages are considered stale;
+// entries for other packages are ignored.
+func (b *baseline) Match(
+	findings []*finding,
+	pkgs map[string]struct{},
+) (suppressed map[findingID]struct{}, stale []*baselineEntry) {
+	remaining := make(map[*baselineEntry]int, len(b.Findings))
+	byKey := make(map[baselineKey][]*baselineEntry)
+	for _, e := range b.Findings {
+		remaining[e] = e.count()
+		byKey[e.key()] = append(byKey[e.key()], e)
+	}
+
+	suppressed = make(map[findingID]struct{})
+	match := func(f *finding, fn func(want, got []string) bool) bool {
+		entry := newBaselineEntry(f)
+		for _, e := range byKey[entry.key()] {
+			if remaining[e] > 0 && fn(entry.Missing, e.Missing) {
+				remaining[e]--
+				suppressed[f.id()] = struct{}{}
+				return true
+			}
+		}
+		return false
+	}
+
+	// Prefer exact matches so that a subset match
+	// doesn't use up an entry that another finding matches exactly.
+	var unmatched []*finding
+	for _, f := range findings {
+		if !match(f, slices.Equal[[]string]) {
+			unmatched = append(unmatched, f)
+		}
+	}
+	for _, f := range unmatched {
+		match(f, isSubset)
+	}
+
+	for _, e := range b.Findings {
+		if _, ok := pkgs[e.Package]; !ok || remaining[e] == 0 {
+			continue
+		}
+		staleEntry := *e
+		staleEntry.Count = remaining[e]
+		stale = append(stale, &staleEntry)
+	}
+
+	return suppressed, stale
+}
+
+// isSubset reports whether all items in sub are in set.
+func isSubset(sub, set []string) bool {
+	for _, s := range sub {
+		if !slices.Contains(set, s) {
+			return false
+		}
+	}
+	return true
+}
+
+func readBaselineFile(path string) (*baseline, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var b baseline
+	if err := json.Unmarshal(bs, &b); err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	return &b, nil
+}
+
+func writeBaselineFile(path string, b *baseline) error {
+	bs, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(bs, '\n'), 0o644)
+}
+
+// funcName returns the name of a function declaration
+// in the form "Func", "Type.Method", or "(*Type).Method".
+func funcName(info *types.Info, decl *ast.FuncDecl) string {
+	fn, ok := info.Defs[decl.Name].(*types.Func)
+	if !ok {
+		return decl.Name.Name
+	}
+
+	recv := fn.Signature().Recv()
+	if recv == nil {
+		return fn.Name()
+	}
+
+	var ptr string
+	typ := recv.Type()
+	if p, ok := typ.(*types.Pointer); ok {
+		ptr = "*"
+		typ = p.Elem()
+	}
+
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok {
+		return fn.Name()
+	}
+	if ptr != "" {
+		return "(" + ptr + named.Obj().Name() + ")." + fn.Name()
+	}
+	return named.Obj().Name() + "." + fn.Name()
+}
@@ -0,0 +1,496 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.abhg.dev/requiredfield"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// _checkFlags lists flags that are handled by checkCmd
+// but not by the standard singlechecker driver.
+// If any of these are present, checkCmd is used instead.
+var _checkFlags = []string{
+	"baseline",
+	"write-baseline",
+	"diff",
+	"diff-base",
+	"format",
+}
+
+// checkCmd runs the analyzer on a list of packages
+// similarly to singlechecker,
+// with support for additional post-processing of diagnostics.
+//
+// It does not support the 'go vet' protocol;
+// that is left to singlechecker.
+type checkCmd struct {
+	Analyzer *analysis.Analyzer // required
+	Stdin    io.Reader          // required
+	Stdout   io.Writer          // required
+	Stderr   io.Writer          // required
+
+	json          bool
+	format        string
+	tests         bool
+	baseline      string
+	writeBaseline string
+	diffBase      string
+	diffFile      string
+}
+
+func (cmd *checkCmd) registerFlags(fs *flag.FlagSet) {
+	cmd.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+
+	fs.BoolVar(&cmd.json, "json", false, "emit JSON output")
+	fs.StringVar(&cmd.format, "format", "text",
+		"output format: text, "+strings.Join(_reportFormats, ", ")+"; reports are written to stdout")
+	fs.BoolVar(&cmd.tests, "test", true, "indicates whether test files should be analyzed, too")
+	fs.StringVar(&cmd.baseline, "baseline", "",
+		"suppress diagnostics recorded in the given baseline file, "+
+			"and report baseline entries that no longer occur")
+	fs.StringVar(&cmd.writeBaseline, "write-baseline", "",
+		"record all current diagnostics to the given baseline file instead of reporting them")
+	fs.StringVar(&cmd.diffBase, "diff-base", "",
+		"report only diagnostics for struct literals changed since the given git revision")
+	fs.StringVar(&cmd.diffFile, "diff", "",
+		"report only diagnostics for struct literals changed by the unified diff in the given file, or stdin if '-'")
+}
+
+// Handles reports whether args need checkCmd
+// instead of the standard singlechecker driver.
+//
+// checkCmd handles args that contain any of the _checkFlags,
+// and runs on package patterns that use only flags it defines,
+// so that the exit code reflects the severity of diagnostics.
+// singlechecker handles the 'go vet' protocol
+// and flags that only it defines, e.g. -fix.
+func (cmd *checkCmd) Handles(args []string) bool {
+	fs := flag.NewFlagSet("requiredfield", flag.ContinueOnError)
+	cmd.registerFlags(fs)
+
+	names, rest := parseFlagNames(fs, args)
+	for _, name := range names {
+		if slices.Contains(_checkFlags, name) {
+			return true
+		}
+	}
+	for _, name := range names {
+		if fs.Lookup(name) == nil {
+			return false
+		}
+	}
+
+	// 'go vet' passes a single JSON configuration file
+	// with a .cfg extension.
+	return len(rest) > 0 && !slices.ContainsFunc(rest, func(arg string) bool {
+		return strings.HasSuffix(arg, ".cfg")
+	})
+}
+
+// Run runs the command with the given arguments
+// and returns the exit code:
+// 0 if there were no diagnostics at the "error" severity,
+// 1 if analysis failed,
+// and 3 if diagnostics at the "error" severity were reported.
+func (cmd *checkCmd) Run(args []string) (exitCode int) {
+	fs := flag.NewFlagSet("requiredfield", flag.ContinueOnError)
+	fs.SetOutput(cmd.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: requiredfield [flags] packages...\n\n")
+		fmt.Fprintf(fs.Output(), "%v\n\nFlags:\n", cmd.Analyzer.Doc)
+		fs.PrintDefaults()
+	}
+	cmd.registerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 1
+	}
+	if cmd.diffBase != "" && cmd.diffFile != "" {
+		fmt.Fprintln(cmd.Stderr, "requiredfield: -diff-base and -diff cannot be used together")
+		return 1
+	}
+	if cmd.format != "text" && !slices.Contains(_reportFormats, cmd.format) {
+		fmt.Fprintf(cmd.Stderr, "requiredfield: unknown format %q: must be one of text, %v\n",
+			cmd.format, strings.Join(_reportFormats, ", "))
+		return 1
+	}
+	if cmd.json && cmd.format != "text" {
+		fmt.Fprintln(cmd.Stderr, "requiredfield: -json and -format cannot be used together")
+		return 1
+	}
+
+	pkgs, err := loadPackages(fs.Args(), cmd.tests)
+	if err != nil {
+		fmt.Fprintf(cmd.Stderr, "requiredfield: %v\n", err)
+		return 1
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		exitCode = 1
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{cmd.Analyzer}, pkgs, nil)
+	if err != nil {
+		fmt.Fprintf(cmd.Stderr, "requiredfield: %v\n", err)
+		return 1
+	}
+
+	if cmd.writeBaseline != "" {
+		if err := writeBaselineFile(cmd.writeBaseline, newBaseline(rootFindings(graph))); err != nil {
+			fmt.Fprintf(cmd.Stderr, "requiredfield: %v\n", err)
+			return 1
+		}
+		return exitCode
+	}
+
+	if cmd.baseline != "" {
+		b, err := readBaselineFile(cmd.baseline)
+		if err != nil {
+			fmt.Fprintf(cmd.Stderr, "requiredfield: %v\n", err)
+			return 1
+		}
+
+		suppressed, stale := b.Match(rootFindings(graph), rootPackages(graph))
+		filterDiagnostics(graph, func(f *finding) bool {
+			_, ok := suppressed[f.id()]
+			return !ok
+		})
+		for _, entry := range stale {
+			fmt.Fprintf(cmd.Stderr, "requiredfield: baseline entry no longer occurs: %v\n", entry)
+		}
+	}
+
+	if cmd.diffBase != "" || cmd.diffFile != "" {
+		root, changed, err := cmd.changedLines()
+		if err != nil {
+			fmt.Fprintf(cmd.Stderr, "requiredfield: %v\n", err)
+			return 1
+		}
+
+		filterDiagnostics(graph, func(f *finding) bool {
+			return f.InDiff(root, changed)
+		})
+	}
+
+	return max(exitCode, cmd.print(graph))
+}
+
+// changedLines returns lines changed by the diff requested with -diff-base or -diff,
+// and the directory that paths in the diff are relative to.
+func (cmd *checkCmd) changedLines() (root string, changed changedLines, err error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", nil, err
+	}
+	root = diffRoot(wd)
+
+	if cmd.diffBase != "" {
+		changed, err = gitDiff(root, cmd.diffBase)
+	} else {
+		changed, err = readDiffFile(cmd.diffFile, cmd.Stdin)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("read diff: %w", err)
+	}
+	return root, changed, nil
+}
+
+// print prints diagnostics for the graph in the requested format
+// and returns the exit code.
+func (cmd *checkCmd) print(graph *checker.Graph) int {
+	// With -json, the exit code is always zero.
+	if cmd.json {
+		if err := graph.PrintJSON(cmd.Stdout); err != nil {
+			return 1
+		}
+		return 0
+	}
+
+	var numErrors, rootErrors int
+	for act := range graph.All() {
+		if act.Err != nil {
+			numErrors++
+			continue
+		}
+		if !act.IsRoot {
+			continue
+		}
+		for _, diag := range act.Diagnostics {
+			if level, _ := requiredfield.DiagnosticSeverity(diag); level == "error" {
+				rootErrors++
+			}
+		}
+	}
+
+	// With a report format, the exit code is zero
+	// unless the analysis fails.
+	if cmd.format != "text" {
+		return cmd.printReport(graph, numErrors)
+	}
+
+	if err := graph.PrintText(cmd.Stderr, -1); err != nil {
+		return 1
+	}
+
+	switch {
+	case numErrors > 0:
+		return 1 // analysis failed, at least partially
+	case rootErrors > 0:
+		return 3 // successfully produced error diagnostics
+	default:
+		return 0
+	}
+}
+
+// printReport writes diagnostics to stdout in the requested -format.
+// Analysis errors are printed to stderr.
+func (cmd *checkCmd) printReport(graph *checker.Graph, numErrors int) int {
+	for act := range graph.All() {
+		if act.Err != nil {
+			fmt.Fprintf(cmd.Stderr, "requiredfield: %v: %v\n", act.Package.PkgPath, act.Err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(cmd.Stderr, "requiredfield: %v\n", err)
+		return 1
+	}
+
+	r := newReport(graph, wd)
+	switch cmd.format {
+	case "sarif":
+		err = r.WriteSARIF(cmd.Stdout, cmd.Analyzer.Name)
+	case "checkstyle":
+		err = r.WriteCheckstyle(cmd.Stdout, cmd.Analyzer.Name)
+	}
+	if err != nil {
+		fmt.Fprintf(cmd.Stderr, "requiredfield: %v\n", err)
+		return 1
+	}
+
+	if numErrors > 0 {
+		return 1
+	}
+	return 0
+}
+
+func loadPackages(patterns []string, tests bool) ([]*packages.Package, error) {
+	cfg := packages.Config{
+		Mode:  packages.LoadAllSyntax | packages.NeedModule,
+		Tests: tests,
+	}
+	pkgs, err := packages.Load(&cfg, patterns...)
+	if err == nil && len(pkgs) == 0 {
+		err = fmt.Errorf("%s matched no packages", strings.Join(patterns, " "))
+	}
+	return pkgs, err
+}
+
+// finding is a diagnostic reported for a root package.
+type finding struct {
+	Package    *packages.Package // required
+	Diagnostic analysis.Diagnostic
+	Position   token.Position
+}
+
+// enclosingNodes returns the struct literal that the finding was reported for
+// and the function declaration that contains it.
+// Either may be nil if not found.
+func (f *finding) enclosingNodes() (lit *ast.CompositeLit, decl *ast.FuncDecl) {
+	pos := f.Diagnostic.Pos
+	file := enclosingFile(f.Package.Syntax, pos)
+	if file == nil {
+		return nil, nil
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil || lit != nil {
+			return false
+		}
+		if n.Pos() > pos || pos >= n.End() {
+			return false
+		}
+
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			decl = n
+		case *ast.CompositeLit:
+			if n.Lbrace == pos {
+				lit = n
+			}
+		}
+		return true
+	})
+	return lit, decl
+}
+
+// InDiff reports whether the struct literal for this finding
+// has any lines changed by the diff.
+// root is the directory that paths in the diff are relative to.
+func (f *finding) InDiff(root string, changed changedLines) bool {
+	path, err := filepath.Rel(evalSymlinks(root), evalSymlinks(f.Position.Filename))
+	if err != nil {
+		return false
+	}
+
+	start, end := f.Position.Line, f.Position.Line
+	if lit, _ := f.enclosingNodes(); lit != nil {
+		start = f.Package.Fset.Position(lit.Pos()).Line
+		end = f.Package.Fset.Position(lit.End()).Line
+	}
+	return changed.ContainsAny(filepath.ToSlash(path), start, end)
+}
+
+// evalSymlinks resolves symbolic links in path,
+// returning it unchanged if that isn't possible.
+func evalSymlinks(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// enclosingFile returns the file in files that contains pos.
+func enclosingFile(files []*ast.File, pos token.Pos) *ast.File {
+	for _, f := range files {
+		if f.FileStart <= pos && pos <= f.FileEnd {
+			return f
+		}
+	}
+	return nil
+}
+
+// findingID identifies a finding by its position and message.
+//
+// Files that are part of multiple packages (e.g. foo and foo.test)
+// report the same finding once for each package.
+// These have the same findingID.
+type findingID struct {
+	Position token.Position
+	Message  string
+}
+
+func (f *finding) id() findingID {
+	return findingID{Position: f.Position, Message: f.Diagnostic.Message}
+}
+
+// rootFindings returns findings reported for root packages
+// in the graph, with duplicates removed.
+func rootFindings(graph *checker.Graph) []*finding {
+	var findings []*finding
+	seen := make(map[findingID]struct{})
+	for _, act := range graph.Roots {
+		for _, diag := range act.Diagnostics {
+			f := &finding{
+				Package:    act.Package,
+				Diagnostic: diag,
+				Position:   act.Package.Fset.Position(diag.Pos),
+			}
+			if _, ok := seen[f.id()]; ok {
+				continue
+			}
+			seen[f.id()] = struct{}{}
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// rootPackages returns the import paths of the root packages in the graph.
+func rootPackages(graph *checker.Graph) map[string]struct{} {
+	pkgs := make(map[string]struct{})
+	for _, act := range graph.Roots {
+		pkgs[act.Package.PkgPath] = struct{}{}
+	}
+	return pkgs
+}
+
+// filterDiagnostics removes diagnostics from root actions in the graph
+// for which keep returns false.
+func filterDiagnostics(graph *checker.Graph, keep func(*finding) bool) {
+	for _, act := range graph.Roots {
+		diags := act.Diagnostics[:0]
+		for _, diag := range act.Diagnostics {
+			f := &finding{
+				Package:    act.Package,
+				Diagnostic: diag,
+				Position:   act.Package.Fset.Position(diag.Pos),
+			}
+			if keep(f) {
+				diags = append(diags, diag)
+			}
+		}
+		act.Diagnostics = diags
+	}
+}
+
+// hasAnyFlag reports whether args contain any of the given flags
+// before the first non-flag argument.
+// See parseFlagNames for how flags are recognized.
+func hasAnyFlag(fs *flag.FlagSet, args []string, names ...string) bool {
+	flags, _ := parseFlagNames(fs, args)
+	for _, name := range flags {
+		if slices.Contains(names, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFlagNames returns the names of flags in args
+// before the first non-flag argument,
+// and the arguments after them.
+// Flags may be specified with one or two leading dashes,
+// and may include a value after "=".
+//
+// fs defines the flags that may appear in args.
+// Values of its non-boolean flags may be given as the next argument,
+// e.g. "-config requiredfield.rc".
+// Flags that fs does not define are assumed to be boolean.
+func parseFlagNames(fs *flag.FlagSet, args []string) (names, rest []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return names, args[i+1:]
+		}
+		if arg == "-" || !strings.HasPrefix(arg, "-") {
+			return names, args[i:]
+		}
+
+		name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		name, _, hasValue := strings.Cut(name, "=")
+		names = append(names, name)
+
+		if f := fs.Lookup(name); f != nil && !hasValue && !isBoolFlag(f) {
+			i++ // skip the value
+		}
+	}
+	return names, nil
+}
+
+// isBoolFlag reports whether f is a boolean flag
+// that doesn't need a value.
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
@@ -0,0 +1,401 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.abhg.dev/requiredfield"
+)
+
+func TestHasAnyFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{name: "empty", args: nil},
+		{name: "other flags", args: []string{"-json", "./..."}},
+		{name: "single dash", args: []string{"-baseline", "b.json", "./..."}, want: true},
+		{name: "double dash", args: []string{"--write-baseline=b.json", "./..."}, want: true},
+		{name: "diff", args: []string{"-diff=-", "./..."}, want: true},
+		{name: "format", args: []string{"-format", "sarif", "./..."}, want: true},
+		{name: "after other flags", args: []string{"-test=false", "-baseline=b.json", "./..."}, want: true},
+		{name: "after packages", args: []string{"./...", "-baseline=b.json"}},
+		{name: "after terminator", args: []string{"--", "-baseline=b.json"}},
+		{name: "prefix", args: []string{"-baselines", "./..."}},
+		{
+			name: "after flag value",
+			args: []string{"-config", "x.rc", "-baseline", "b.json", "./..."},
+			want: true,
+		},
+		{
+			name: "after required",
+			args: []string{"-required", "x.T.F", "-format=sarif", "./..."},
+			want: true,
+		},
+		{name: "flag value", args: []string{"-config", "-baseline", "./..."}},
+		{name: "after bool flag", args: []string{"-promote", "-diff-base", "main", "./..."}, want: true},
+		{name: "after unknown flag", args: []string{"-fix", "-baseline=b.json", "./..."}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := checkCmd{Analyzer: requiredfield.New(requiredfield.Options{})}
+			fs := flag.NewFlagSet("requiredfield", flag.ContinueOnError)
+			cmd.registerFlags(fs)
+
+			got := hasAnyFlag(fs, tt.args, _checkFlags...)
+			if got != tt.want {
+				t.Errorf("hasAnyFlag(%q) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCmd_Handles(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{name: "empty", args: nil},
+		{name: "packages", args: []string{"./..."}, want: true},
+		{name: "known flags", args: []string{"-json", "-config", "x.rc", "./..."}, want: true},
+		{name: "check flag", args: []string{"-baseline", "b.json", "./..."}, want: true},
+		{name: "singlechecker flag", args: []string{"-fix", "./..."}},
+		{name: "check and singlechecker flags", args: []string{"-fix", "-baseline=b.json", "./..."}, want: true},
+		{name: "vet flags", args: []string{"-flags"}},
+		{name: "vet version", args: []string{"-V=full"}},
+		{name: "vet config", args: []string{"-config=x.rc", "vet.cfg"}},
+		{name: "help", args: []string{"-h"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := checkCmd{Analyzer: requiredfield.New(requiredfield.Options{})}
+			if got := cmd.Handles(tt.args); got != tt.want {
+				t.Errorf("Handles(%q) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCmd_baseline(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/foo\n\ngo 1.22\n",
+		"foo.go": joinLines(
+			"package foo",
+			"",
+			"type User struct {",
+			"	ID   string // required",
+			"	Name string // required",
+			"}",
+			"",
+			"type Server struct{}",
+			"",
+			"func (*Server) Start() {",
+			"	_ = User{}",
+			"}",
+			"",
+			"func NewUser() User {",
+			"	return User{Name: \"x\"}",
+			"}",
+		),
+	})
+	t.Chdir(dir)
+
+	baselineFile := filepath.Join(dir, "baseline.json")
+	if _, code, stderr := runCheck(t, "-write-baseline", baselineFile, "./..."); code != 0 {
+		t.Fatalf("write baseline: exit code %d:\n%s", code, stderr)
+	}
+
+	bs, err := os.ReadFile(baselineFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"function": "(*Server).Start"`,
+		`"function": "NewUser"`,
+		`"type": "example.com/foo.User"`,
+	} {
+		if !bytes.Contains(bs, []byte(want)) {
+			t.Errorf("baseline does not contain %s:\n%s", want, bs)
+		}
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		_, code, stderr := runCheck(t, "-baseline", baselineFile, "./...")
+		if code != 0 {
+			t.Errorf("exit code = %d, want 0:\n%s", code, stderr)
+		}
+	})
+
+	t.Run("edited", func(t *testing.T) {
+		// Shift lines, fix one field, and add a new violation.
+		writeFiles(t, dir, map[string]string{
+			"foo.go": joinLines(
+				"package foo",
+				"",
+				"// User is a user.",
+				"type User struct {",
+				"	ID   string // required",
+				"	Name string // required",
+				"}",
+				"",
+				"type Server struct{}",
+				"",
+				"func (*Server) Start() {",
+				"	_ = User{ID: \"x\"}",
+				"}",
+				"",
+				"func NewUser() User {",
+				"	return User{ID: \"x\", Name: \"x\"}",
+				"}",
+				"",
+				"func Other() User {",
+				"	return User{}",
+				"}",
+			),
+		})
+
+		_, code, stderr := runCheck(t, "-baseline", baselineFile, "./...")
+		if code != 3 {
+			t.Errorf("exit code = %d, want 3:\n%s", code, stderr)
+		}
+
+		if !strings.Contains(stderr, "foo.go:20:") || !strings.Contains(stderr, "missing required fields: ID, Name") {
+			t.Errorf("new violation not reported:\n%s", stderr)
+		}
+		if strings.Contains(stderr, "foo.go:12:") {
+			t.Errorf("baselined violation reported:\n%s", stderr)
+		}
+		if !strings.Contains(stderr, "baseline entry no longer occurs: example.com/foo: NewUser: example.com/foo.User: missing ID") {
+			t.Errorf("stale baseline entry not reported:\n%s", stderr)
+		}
+	})
+}
+
+func TestCheckCmd_diff(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/foo\n\ngo 1.22\n",
+		"foo.go": joinLines(
+			"package foo",
+			"",
+			"type User struct {",
+			"	ID   string // required",
+			"	Name string // required",
+			"}",
+			"",
+			"func Old() User {",
+			"	return User{}",
+			"}",
+		),
+	})
+	t.Chdir(dir)
+
+	git := func(args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", args...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %q: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+
+	// A literal spanning multiple lines is reported
+	// if any of its lines change.
+	writeFiles(t, dir, map[string]string{
+		"foo.go": joinLines(
+			"package foo",
+			"",
+			"type User struct {",
+			"	ID   string // required",
+			"	Name string // required",
+			"}",
+			"",
+			"func Old() User {",
+			"	return User{}",
+			"}",
+			"",
+			"func New() User {",
+			"	return User{",
+			"		ID: \"x\",",
+			"	}",
+			"}",
+		),
+	})
+
+	t.Run("diff-base", func(t *testing.T) {
+		_, code, stderr := runCheck(t, "-diff-base", "HEAD", "./...")
+		if code != 3 {
+			t.Errorf("exit code = %d, want 3:\n%s", code, stderr)
+		}
+		if !strings.Contains(stderr, "foo.go:13:") {
+			t.Errorf("changed literal not reported:\n%s", stderr)
+		}
+		if strings.Contains(stderr, "foo.go:9:") {
+			t.Errorf("unchanged literal reported:\n%s", stderr)
+		}
+	})
+
+	t.Run("diff file", func(t *testing.T) {
+		patch := joinLines(
+			"--- a/foo.go",
+			"+++ b/foo.go",
+			"@@ -14 +14 @@ func New() User {",
+			"-		ID: \"y\",",
+			"+		ID: \"x\",",
+		)
+		writeFiles(t, dir, map[string]string{"changes.patch": patch})
+
+		_, code, stderr := runCheck(t, "-diff", "changes.patch", "./...")
+		if code != 3 {
+			t.Errorf("exit code = %d, want 3:\n%s", code, stderr)
+		}
+		if !strings.Contains(stderr, "foo.go:13:") {
+			t.Errorf("changed literal not reported:\n%s", stderr)
+		}
+		if strings.Contains(stderr, "foo.go:9:") {
+			t.Errorf("unchanged literal reported:\n%s", stderr)
+		}
+	})
+
+	t.Run("unrelated diff", func(t *testing.T) {
+		patch := joinLines(
+			"--- a/foo.go",
+			"+++ b/foo.go",
+			"@@ -2,0 +3 @@",
+			"+// User is a user.",
+		)
+		writeFiles(t, dir, map[string]string{"changes.patch": patch})
+
+		_, code, stderr := runCheck(t, "-diff", "changes.patch", "./...")
+		if code != 0 {
+			t.Errorf("exit code = %d, want 0:\n%s", code, stderr)
+		}
+	})
+
+	// A literal is reported if lines are only deleted from it.
+	t.Run("deleted line", func(t *testing.T) {
+		newFunc := func(fields ...string) string {
+			return joinLines(append(append([]string{
+				"package foo",
+				"",
+				"type User struct {",
+				"	ID   string // required",
+				"	Name string // required",
+				"}",
+				"",
+				"func Old() User {",
+				"	return User{}",
+				"}",
+				"",
+				"func New() User {",
+				"	return User{",
+			}, fields...), "	}", "}")...)
+		}
+		writeFiles(t, dir, map[string]string{
+			"foo.go": newFunc("		ID:   \"x\",", "		Name: \"y\","),
+		})
+		git("add", "foo.go")
+		git("commit", "-q", "-m", "set all fields")
+
+		writeFiles(t, dir, map[string]string{
+			"foo.go": newFunc("		Name: \"y\","),
+		})
+
+		_, code, stderr := runCheck(t, "-diff-base", "HEAD", "./...")
+		if code != 3 {
+			t.Errorf("exit code = %d, want 3:\n%s", code, stderr)
+		}
+		if !strings.Contains(stderr, "foo.go:13:") {
+			t.Errorf("changed literal not reported:\n%s", stderr)
+		}
+		if strings.Contains(stderr, "foo.go:9:") {
+			t.Errorf("unchanged literal reported:\n%s", stderr)
+		}
+	})
+}
+
+func TestCheckCmd_severity(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/severity\n\ngo 1.22\n",
+		"foo.go": joinLines(
+			"package foo",
+			"",
+			"type User struct {",
+			"	ID   string",
+			"	Name string",
+			"}",
+			"",
+			"var _ = User{}",
+		),
+		"warning.rc": "required example.com/severity.User.Name severity=warning\n",
+		"error.rc":   "required example.com/severity.User.ID\n",
+	})
+	t.Chdir(dir)
+
+	t.Run("warning", func(t *testing.T) {
+		_, code, stderr := runCheck(t, "-config", "warning.rc", "./...")
+		if code != 0 {
+			t.Errorf("exit code = %d, want 0:\n%s", code, stderr)
+		}
+		if want := "warning: missing required fields: Name"; !strings.Contains(stderr, want) {
+			t.Errorf("stderr = %q, want to contain %q", stderr, want)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, code, stderr := runCheck(t, "-config", "warning.rc", "-config", "error.rc", "./...")
+		if code != 3 {
+			t.Errorf("exit code = %d, want 3:\n%s", code, stderr)
+		}
+	})
+}
+
+func runCheck(t *testing.T, args ...string) (stdout string, exitCode int, stderr string) {
+	t.Helper()
+
+	var outBuf, errBuf bytes.Buffer
+	cmd := checkCmd{
+		Analyzer: requiredfield.New(requiredfield.Options{}),
+		Stdin:    strings.NewReader(""),
+		Stdout:   &outBuf,
+		Stderr:   &errBuf,
+	}
+	exitCode = cmd.Run(args)
+	return outBuf.String(), exitCode, errBuf.String()
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %v: %v", name, err)
+		}
+	}
+}
+
+func joinLines(lines ...string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
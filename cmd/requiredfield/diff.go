@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// changedLines records lines added or modified by a diff.
+//
+// Paths are slash-separated and relative to the root of the diff,
+// and line numbers are 1-indexed and refer to the new version of each file.
+type changedLines map[string]map[int]struct{}
+
+// ContainsAny reports whether any line in [start, end]
+// of the given file was changed.
+func (c changedLines) ContainsAny(path string, start, end int) bool {
+	lines := c[path]
+	for line := start; line <= end; line++ {
+		if _, ok := lines[line]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseUnifiedDiff parses a unified diff
+// (as produced by 'git diff' or 'diff -u')
+// and returns the lines that it adds or modifies.
+//
+// Deleted lines don't exist in the new version of the file,
+// so lines that are only deleted record the lines around them instead.
+func parseUnifiedDiff(r io.Reader) (changedLines, error) {
+	changed := make(changedLines)
+
+	var (
+		lines   map[int]struct{} // lines for the current file, or nil if deleted
+		hunk    hunkHeader       // remaining lines in the current hunk
+		lineNum int
+
+		// deleted is set if lines were deleted
+		// at the current position without adding any lines.
+		deleted bool
+	)
+	markDeleted := func() {
+		if deleted && lines != nil {
+			if hunk.NewStart > 1 {
+				lines[hunk.NewStart-1] = struct{}{}
+			}
+			lines[hunk.NewStart] = struct{}{}
+		}
+		deleted = false
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+
+		if hunk.OldLines > 0 || hunk.NewLines > 0 {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				deleted = false
+				if lines != nil {
+					lines[hunk.NewStart] = struct{}{}
+				}
+				hunk.NewStart++
+				hunk.NewLines--
+
+			case strings.HasPrefix(line, "-"):
+				deleted = true
+				hunk.OldLines--
+
+			case strings.HasPrefix(line, `\`):
+				// "\ No newline at end of file"
+
+			default:
+				// Context line.
+				// Some tools strip the leading space from empty lines.
+				markDeleted()
+				hunk.NewStart++
+				hunk.NewLines--
+				hunk.OldLines--
+			}
+			if hunk.OldLines <= 0 && hunk.NewLines <= 0 {
+				markDeleted()
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			lines = nil
+
+			path := diffPath(line[len("+++ "):])
+			if path == "" {
+				continue // file was deleted
+			}
+			lines = changed[path]
+			if lines == nil {
+				lines = make(map[int]struct{})
+				changed[path] = lines
+			}
+
+		case strings.HasPrefix(line, "@@ "):
+			var err error
+			hunk, err = parseHunkHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("%d:%w", lineNum, err)
+			}
+		}
+
+		// Ignore file headers and other metadata.
+	}
+
+	return changed, scanner.Err()
+}
+
+// diffPath returns the path of a file in a "+++" line,
+// or an empty string if the file was deleted.
+func diffPath(s string) string {
+	// Drop timestamps added by diff -u.
+	if path, _, ok := strings.Cut(s, "\t"); ok {
+		s = path
+	}
+	s = strings.TrimSpace(s)
+	if s == "/dev/null" {
+		return ""
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	// Drop the "b/" prefix added by git.
+	if path, ok := strings.CutPrefix(s, "b/"); ok {
+		s = path
+	}
+	return filepath.ToSlash(filepath.Clean(s))
+}
+
+// hunkHeader is the range information in a hunk header.
+type hunkHeader struct {
+	OldLines int // number of lines in the old file
+	NewStart int // first line in the new file after the hunk starts
+	NewLines int // number of lines in the new file
+}
+
+// parseHunkHeader parses a hunk header in the form
+// "@@ -l,s +l,s @@".
+// The line counts default to 1 if omitted.
+func parseHunkHeader(line string) (hunkHeader, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return hunkHeader{}, fmt.Errorf("bad hunk header %q", line)
+	}
+
+	_, oldLines, err := parseHunkRange(fields[1][1:])
+	if err != nil {
+		return hunkHeader{}, fmt.Errorf("bad hunk header %q: %w", line, err)
+	}
+	newStart, newLines, err := parseHunkRange(fields[2][1:])
+	if err != nil {
+		return hunkHeader{}, fmt.Errorf("bad hunk header %q: %w", line, err)
+	}
+
+	// Empty ranges start at the line before the hunk,
+	// e.g. "@@ -10,2 +9,0 @@" deletes lines after line 9.
+	if newLines == 0 {
+		newStart++
+	}
+
+	return hunkHeader{
+		OldLines: oldLines,
+		NewStart: newStart,
+		NewLines: newLines,
+	}, nil
+}
+
+// parseHunkRange parses a range in the form "l,s" or "l".
+func parseHunkRange(s string) (start, count int, err error) {
+	startStr, countStr, ok := strings.Cut(s, ",")
+	start, err = strconv.Atoi(startStr)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	count = 1
+	if ok {
+		count, err = strconv.Atoi(countStr)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return start, count, nil
+}
+
+// readDiffFile reads a unified diff from the given file,
+// or from stdin if path is "-".
+func readDiffFile(path string, stdin io.Reader) (changedLines, error) {
+	if path == "-" {
+		return parseUnifiedDiff(stdin)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	changed, err := parseUnifiedDiff(f)
+	if err != nil {
+		return nil, fmt.Errorf("%v:%w", path, err)
+	}
+	return changed, nil
+}
+
+// gitDiff reports lines changed in the working tree
+// since the merge base of HEAD and the given revision.
+//
+// Untracked files are not included.
+func gitDiff(dir, rev string) (changedLines, error) {
+	// Compare against the merge base so that changes made to rev
+	// since the current branch was created aren't included.
+	base := rev
+	if out, err := runGit(dir, "merge-base", rev, "HEAD"); err == nil {
+		base = strings.TrimSpace(string(out))
+	}
+
+	out, err := runGit(dir, "diff", "-U0", "--no-color", "--no-ext-diff", "--no-renames", base, "--")
+	if err != nil {
+		return nil, err
+	}
+	return parseUnifiedDiff(bytes.NewReader(out))
+}
+
+// diffRoot returns the directory that paths in a diff are relative to:
+// the root of the git repository containing dir if there is one,
+// and dir otherwise.
+func diffRoot(dir string) string {
+	out, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return dir
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func runGit(dir string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = errors.New(msg)
+		}
+		return nil, fmt.Errorf("git %v: %w", args[0], err)
+	}
+	return out, nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		give string
+		want map[string][]int
+	}{
+		{
+			name: "git zero context",
+			give: joinLines(
+				"diff --git a/foo.go b/foo.go",
+				"index 1234567..89abcde 100644",
+				"--- a/foo.go",
+				"+++ b/foo.go",
+				"@@ -3 +3,2 @@ type Foo struct {",
+				"-	A string",
+				"+	A string // required",
+				"+	B string",
+				"@@ -10,2 +11,0 @@ func x() {",
+				"-	foo()",
+				"-	bar()",
+				"diff --git a/bar/bar.go b/bar/bar.go",
+				"--- a/bar/bar.go",
+				"+++ b/bar/bar.go",
+				"@@ -1 +1 @@",
+				"-package bar",
+				"+package baz",
+			),
+			want: map[string][]int{
+				"foo.go":     {3, 4, 11, 12},
+				"bar/bar.go": {1},
+			},
+		},
+		{
+			name: "context lines",
+			give: joinLines(
+				"--- foo.go	2026-10-19 10:00:00",
+				"+++ foo.go	2026-10-19 10:01:00",
+				"@@ -1,4 +1,5 @@",
+				" package foo",
+				"",
+				"-var x = 1",
+				"+var x = 2",
+				"+var y = 3",
+				" var z = 4",
+			),
+			want: map[string][]int{
+				"foo.go": {3, 4},
+			},
+		},
+		{
+			name: "deleted lines",
+			give: joinLines(
+				"--- a/foo.go",
+				"+++ b/foo.go",
+				"@@ -1,5 +1,3 @@",
+				" x := Foo{",
+				"-	ID:   \"x\",",
+				"-	Name: \"y\",",
+				" }",
+				" y := 1",
+			),
+			want: map[string][]int{
+				"foo.go": {1, 2},
+			},
+		},
+		{
+			name: "lines that look like headers",
+			give: joinLines(
+				"--- a/foo.go",
+				"+++ b/foo.go",
+				"@@ -1,2 +1,2 @@",
+				"--- a/bar.go",
+				"+++ b/bar.go",
+				" x",
+			),
+			want: map[string][]int{
+				"foo.go": {1},
+			},
+		},
+		{
+			name: "deleted file",
+			give: joinLines(
+				"--- a/foo.go",
+				"+++ /dev/null",
+				"@@ -1 +0,0 @@",
+				"-package foo",
+			),
+			want: map[string][]int{},
+		},
+		{
+			name: "new file",
+			give: joinLines(
+				"--- /dev/null",
+				"+++ b/foo.go",
+				"@@ -0,0 +1,2 @@",
+				"+package foo",
+				"+",
+				"\\ No newline at end of file",
+			),
+			want: map[string][]int{
+				"foo.go": {1, 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed, err := parseUnifiedDiff(strings.NewReader(tt.give))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := make(map[string][]int)
+			for path, lines := range changed {
+				got[path] = []int{}
+				for line := 1; line <= 100; line++ {
+					if _, ok := lines[line]; ok {
+						got[path] = append(got[path], line)
+					}
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseUnifiedDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnifiedDiff_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		give    string
+		wantErr string
+	}{
+		{
+			name:    "missing new range",
+			give:    joinLines("+++ b/foo.go", "@@ -1 @@"),
+			wantErr: `2:bad hunk header "@@ -1 @@"`,
+		},
+		{
+			name:    "bad line number",
+			give:    joinLines("+++ b/foo.go", "@@ -1 +x,2 @@"),
+			wantErr: `2:bad hunk header "@@ -1 +x,2 @@"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseUnifiedDiff(strings.NewReader(tt.give))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseUnifiedDiff() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,456 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"slices"
+
+	"go.abhg.dev/requiredfield"
+	"go.abhg.dev/requiredfield/internal/fixes"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// inferCmd implements the 'infer' subcommand,
+// which suggests fields that should be required
+// based on how often they're set in struct literals.
+type inferCmd struct {
+	Analyzer *analysis.Analyzer // required
+	Stdout   io.Writer          // required
+	Stderr   io.Writer          // required
+
+	threshold   float64
+	minLiterals int
+	format      string
+	fix         bool
+	tests       bool
+}
+
+func (cmd *inferCmd) registerFlags(fs *flag.FlagSet) {
+	cmd.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+
+	fs.Float64Var(&cmd.threshold, "threshold", 90,
+		"suggest fields set in at least this percentage of keyed literals of their type")
+	fs.IntVar(&cmd.minLiterals, "min-literals", 5,
+		"ignore types with fewer than this many keyed literals")
+	fs.StringVar(&cmd.format, "format", "text", "output format: text or rc")
+	fs.BoolVar(&cmd.fix, "fix", false,
+		"add '// required' comments to suggested fields declared in the given packages")
+	fs.BoolVar(&cmd.tests, "test", true, "indicates whether test files should be analyzed, too")
+}
+
+// Run runs the command with the given arguments
+// and returns the exit code.
+func (cmd *inferCmd) Run(args []string) int {
+	fs := flag.NewFlagSet("requiredfield infer", flag.ContinueOnError)
+	fs.SetOutput(cmd.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: requiredfield infer [flags] packages...\n\n")
+		fmt.Fprintf(fs.Output(), "Suggest required fields based on how often they're set in struct literals.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	cmd.registerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 1
+	}
+	if cmd.threshold <= 0 || cmd.threshold > 100 {
+		fmt.Fprintf(cmd.Stderr, "requiredfield: -threshold must be in (0, 100], got %v\n", cmd.threshold)
+		return 1
+	}
+
+	var write func(io.Writer, []*inference) error
+	switch cmd.format {
+	case "text":
+		write = writeInferText
+	case "rc":
+		write = writeInferRC
+	default:
+		fmt.Fprintf(cmd.Stderr, "requiredfield: unknown format %q: must be one of text, rc\n", cmd.format)
+		return 1
+	}
+
+	infs, err := cmd.infer(fs.Args())
+	if err != nil {
+		fmt.Fprintf(cmd.Stderr, "requiredfield: %v\n", err)
+		return 1
+	}
+
+	if cmd.fix {
+		if err := cmd.applyFixes(infs); err != nil {
+			fmt.Fprintf(cmd.Stderr, "requiredfield: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if err := write(cmd.Stdout, infs); err != nil {
+		fmt.Fprintf(cmd.Stderr, "requiredfield: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// inference is a field suggested to be required.
+type inference struct {
+	Type     string // "package/path.Type"
+	Field    *types.Var
+	Set      int // number of keyed literals that set the field
+	Literals int // number of keyed literals of the type
+
+	// Diagnostic suggests marking the field as required.
+	// It has a SuggestedFix if the field is declared
+	// in one of the analyzed packages
+	// and every field declared with it is suggested too.
+	Diagnostic analysis.Diagnostic
+	Fset       *token.FileSet
+
+	// NoFix explains why Diagnostic doesn't have a SuggestedFix.
+	NoFix string
+}
+
+// literalStats records how often fields of a struct type
+// are set in keyed literals.
+type literalStats struct {
+	Type     *types.Named // generic origin type
+	Literals int
+	Set      map[string]int // field name -> number of literals
+
+	// Required is the set of fields that are already required
+	// in any of the packages with literals of the type.
+	Required map[string]struct{}
+}
+
+// infer analyzes the given packages
+// and returns fields that should probably be required,
+// ordered by type and then by field declaration order.
+func (cmd *inferCmd) infer(patterns []string) ([]*inference, error) {
+	pkgs, err := loadPackages(patterns, cmd.tests)
+	if err != nil {
+		return nil, err
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		return nil, errors.New("packages contain errors")
+	}
+
+	// Run the analyzer to find fields that are already required.
+	graph, err := checker.Analyze([]*analysis.Analyzer{cmd.Analyzer}, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+	fieldDecls := make(map[*types.Var]*ast.Field)
+	fsets := make(map[*types.Var]*token.FileSet)
+	stats := make(map[string]*literalStats)
+	seenFiles := make(map[string]struct{})
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return nil, fmt.Errorf("%v: %w", act.Package.PkgPath, act.Err)
+		}
+		result := act.Result.(*requiredfield.RequiredFields)
+
+		pkg := act.Package
+		for _, file := range pkg.Syntax {
+			// Test variants of a package include the same files.
+			name := pkg.Fset.File(file.FileStart).Name()
+			if _, ok := seenFiles[name]; ok {
+				continue
+			}
+			seenFiles[name] = struct{}{}
+
+			collectLiteralStats(stats, pkg.TypesInfo, result, file)
+			for v, field := range fieldDeclarations(pkg.TypesInfo, file) {
+				fieldDecls[v] = field
+				fsets[v] = pkg.Fset
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(stats))
+	for key := range stats {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var infs []*inference
+	for _, key := range keys {
+		s := stats[key]
+		if s.Literals < cmd.minLiterals {
+			continue
+		}
+
+		var typeInfs []*inference
+		suggested := make(map[string]struct{})
+		st := s.Type.Underlying().(*types.Struct)
+		for i := range st.NumFields() {
+			field := st.Field(i)
+			set := s.Set[field.Name()]
+			if set == 0 || float64(set)*100 < cmd.threshold*float64(s.Literals) {
+				continue
+			}
+			if _, ok := s.Required[field.Name()]; ok {
+				continue
+			}
+
+			inf := &inference{
+				Type:     key,
+				Field:    field,
+				Set:      set,
+				Literals: s.Literals,
+				Fset:     fsets[field],
+				Diagnostic: analysis.Diagnostic{
+					Pos: field.Pos(),
+					Message: fmt.Sprintf("%v.%v is set in %d of %d keyed literals (%.0f%%)",
+						key, field.Name(), set, s.Literals, float64(set)*100/float64(s.Literals)),
+				},
+			}
+			typeInfs = append(typeInfs, inf)
+			suggested[field.Name()] = struct{}{}
+		}
+
+		for _, inf := range typeInfs {
+			decl, ok := fieldDecls[inf.Field]
+			if !ok {
+				inf.NoFix = "is not declared in the analyzed packages"
+				continue
+			}
+
+			// Comments apply to all names in a field (A, B int),
+			// so only offer the fix if all of them are suggested.
+			allSuggested := true
+			for _, id := range decl.Names {
+				if _, ok := suggested[id.Name]; !ok {
+					allSuggested = false
+				}
+			}
+			if !allSuggested {
+				inf.NoFix = "is declared together with fields that aren't suggested"
+				continue
+			}
+
+			inf.Diagnostic.SuggestedFixes = []analysis.SuggestedFix{
+				requiredCommentFix(inf.Field.Name(), decl),
+			}
+		}
+		infs = append(infs, typeInfs...)
+	}
+
+	return infs, nil
+}
+
+// collectLiteralStats records fields set in keyed literals
+// of named struct types in the given file.
+//
+// Literals with unkeyed elements are ignored
+// because they must set all fields.
+// Fields that are already required are looked up in result.
+func collectLiteralStats(
+	stats map[string]*literalStats,
+	info *types.Info,
+	result *requiredfield.RequiredFields,
+	file *ast.File,
+) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+
+		named, ok := types.Unalias(info.TypeOf(lit)).(*types.Named)
+		if !ok {
+			return true
+		}
+		named = named.Origin()
+		if _, ok := named.Underlying().(*types.Struct); !ok || named.Obj().Pkg() == nil {
+			return true
+		}
+
+		if len(lit.Elts) > 0 {
+			if _, ok := lit.Elts[0].(*ast.KeyValueExpr); !ok {
+				return true
+			}
+		}
+
+		key := named.Obj().Pkg().Path() + "." + named.Obj().Name()
+		s, ok := stats[key]
+		if !ok {
+			s = &literalStats{
+				Type:     named,
+				Set:      make(map[string]int),
+				Required: make(map[string]struct{}),
+			}
+			stats[key] = s
+		}
+		for _, name := range result.Required(named) {
+			s.Required[name] = struct{}{}
+		}
+		s.Literals++
+		for _, elt := range lit.Elts {
+			kv := elt.(*ast.KeyValueExpr)
+			if id, ok := kv.Key.(*ast.Ident); ok {
+				s.Set[id.Name]++
+			}
+		}
+		return true
+	})
+}
+
+// fieldDeclarations returns the declarations of fields
+// of named struct types declared at the top level of the given file.
+func fieldDeclarations(info *types.Info, file *ast.File) map[*types.Var]*ast.Field {
+	decls := make(map[*types.Var]*ast.Field)
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range decl.Specs {
+			st, ok := spec.(*ast.TypeSpec).Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			for _, field := range st.Fields.List {
+				for _, id := range field.Names {
+					if v, ok := info.Defs[id].(*types.Var); ok {
+						decls[v] = field
+					}
+				}
+			}
+		}
+	}
+	return decls
+}
+
+// requiredCommentFix returns a fix that marks the given field as required
+// by adding a "// required" comment at the end of its declaration.
+func requiredCommentFix(name string, field *ast.Field) analysis.SuggestedFix {
+	return analysis.SuggestedFix{
+		Message:   fmt.Sprintf("Mark %v as required", name),
+		TextEdits: []analysis.TextEdit{fixes.MarkRequired(field)},
+	}
+}
+
+// applyFixes applies suggested fixes for the given inferences,
+// and reports fields that couldn't be fixed.
+func (cmd *inferCmd) applyFixes(infs []*inference) error {
+	edits := make(map[string][]fileEdit)
+	seen := make(map[token.Pos]struct{})
+	for _, inf := range infs {
+		if len(inf.Diagnostic.SuggestedFixes) == 0 {
+			fmt.Fprintf(cmd.Stderr, "requiredfield: %v.%v %v\n",
+				inf.Type, inf.Field.Name(), inf.NoFix)
+			continue
+		}
+
+		for _, edit := range inf.Diagnostic.SuggestedFixes[0].TextEdits {
+			// Fields declared together (A, B int)
+			// share the same edit.
+			if _, ok := seen[edit.Pos]; ok {
+				continue
+			}
+			seen[edit.Pos] = struct{}{}
+
+			pos := inf.Fset.Position(edit.Pos)
+			edits[pos.Filename] = append(edits[pos.Filename], fileEdit{
+				Offset:  pos.Offset,
+				NewText: edit.NewText,
+			})
+		}
+	}
+
+	filenames := make([]string, 0, len(edits))
+	for name := range edits {
+		filenames = append(filenames, name)
+	}
+	slices.Sort(filenames)
+
+	for _, name := range filenames {
+		if err := applyFileEdits(name, edits[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileEdit is an insertion into a file at a byte offset.
+type fileEdit struct {
+	Offset  int
+	NewText []byte
+}
+
+func applyFileEdits(filename string, edits []fileEdit) error {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	// Apply from the end so that offsets remain valid.
+	slices.SortFunc(edits, func(a, b fileEdit) int {
+		return cmp.Compare(b.Offset, a.Offset)
+	})
+	for _, e := range edits {
+		src = slices.Concat(src[:e.Offset], e.NewText, src[e.Offset:])
+	}
+
+	// Re-align comments.
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("%v: %w", filename, err)
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, formatted, info.Mode())
+}
+
+func writeInferText(w io.Writer, infs []*inference) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	for _, inf := range infs {
+		pos := "-"
+		if inf.Fset != nil {
+			p := inf.Fset.Position(inf.Diagnostic.Pos)
+			p.Filename = relPath(wd, p.Filename)
+			pos = p.String()
+		}
+		if _, err := fmt.Fprintf(w, "%v: %v\n", pos, inf.Diagnostic.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeInferRC writes inferences in the requiredfield.rc format.
+func writeInferRC(w io.Writer, infs []*inference) error {
+	var buf bytes.Buffer
+	for _, inf := range infs {
+		fmt.Fprintf(&buf, "# set in %d of %d keyed literals\n", inf.Set, inf.Literals)
+		fmt.Fprintf(&buf, "required %v.%v\n", inf.Type, inf.Field.Name())
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"go.abhg.dev/requiredfield"
+)
+
+func TestInferCmd(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/infer\n\ngo 1.22\n",
+		"user.go": joinLines(
+			"package infer",
+			"",
+			"type User struct {",
+			"	ID    string",
+			"	Name  string // display name",
+			"	Email string // required",
+			"	Age   int",
+			"}",
+			"",
+			"type Point struct{ X, Y int }",
+		),
+		"use.go": joinLines(
+			"package infer",
+			"",
+			"var users = []User{",
+			"	{ID: \"1\", Name: \"a\", Email: \"a\"},",
+			"	{ID: \"2\", Name: \"b\", Email: \"b\"},",
+			"	{ID: \"3\", Name: \"c\", Email: \"c\", Age: 3},",
+			"	{ID: \"4\", Email: \"d\"},",
+			"}",
+			"",
+			"var points = []Point{{1, 2}, {3, 4}, {X: 5, Y: 6}}",
+		),
+	})
+	t.Chdir(dir)
+
+	t.Run("text", func(t *testing.T) {
+		stdout, code, stderr := runInfer(t, "-min-literals", "2", "-threshold", "75", "./...")
+		if code != 0 {
+			t.Fatalf("exit code = %d, want 0:\n%s", code, stderr)
+		}
+
+		want := joinLines(
+			"user.go:4:2: example.com/infer.User.ID is set in 4 of 4 keyed literals (100%)",
+			"user.go:5:2: example.com/infer.User.Name is set in 3 of 4 keyed literals (75%)",
+		)
+		if stdout != want {
+			t.Errorf("output:\n%s\nwant:\n%s", stdout, want)
+		}
+	})
+
+	t.Run("rc", func(t *testing.T) {
+		stdout, code, stderr := runInfer(t, "-min-literals", "2", "-format", "rc", "./...")
+		if code != 0 {
+			t.Fatalf("exit code = %d, want 0:\n%s", code, stderr)
+		}
+
+		want := joinLines(
+			"# set in 4 of 4 keyed literals",
+			"required example.com/infer.User.ID",
+		)
+		if stdout != want {
+			t.Errorf("output:\n%s\nwant:\n%s", stdout, want)
+		}
+	})
+
+	t.Run("min literals", func(t *testing.T) {
+		stdout, code, stderr := runInfer(t, "./...")
+		if code != 0 {
+			t.Fatalf("exit code = %d, want 0:\n%s", code, stderr)
+		}
+		if stdout != "" {
+			t.Errorf("expected no suggestions, got:\n%s", stdout)
+		}
+	})
+
+	t.Run("fix", func(t *testing.T) {
+		_, code, stderr := runInfer(t, "-min-literals", "2", "-threshold", "75", "-fix", "./...")
+		if code != 0 {
+			t.Fatalf("exit code = %d, want 0:\n%s", code, stderr)
+		}
+
+		got, err := os.ReadFile("user.go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := joinLines(
+			"package infer",
+			"",
+			"type User struct {",
+			"	ID    string // required",
+			"	Name  string // required // display name",
+			"	Email string // required",
+			"	Age   int",
+			"}",
+			"",
+			"type Point struct{ X, Y int }",
+		)
+		if string(got) != want {
+			t.Errorf("user.go:\n%s\nwant:\n%s", got, want)
+		}
+	})
+}
+
+func TestInferCmd_alreadyRequired(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/infer\n\ngo 1.22\n",
+		"dep/dep.go": joinLines(
+			"package dep",
+			"",
+			"type Item struct {",
+			"	ID    string // required",
+			"	Name  string",
+			"	Price int",
+			"}",
+		),
+		"use/use.go": joinLines(
+			"package use",
+			"",
+			"import \"example.com/infer/dep\"",
+			"",
+			"var items = []dep.Item{",
+			"	{ID: \"1\", Name: \"a\", Price: 1},",
+			"	{ID: \"2\", Name: \"b\", Price: 2},",
+			"}",
+		),
+	})
+	t.Chdir(dir)
+
+	// Fields required in a dependency or by configuration
+	// are not suggested.
+	stdout, code, stderr := runInfer(t,
+		"-min-literals", "2",
+		"-required", "example.com/infer/dep.Item.Name",
+		"./use",
+	)
+	if code != 0 {
+		t.Fatalf("exit code = %d, want 0:\n%s", code, stderr)
+	}
+
+	want := joinLines(
+		"-: example.com/infer/dep.Item.Price is set in 2 of 2 keyed literals (100%)",
+	)
+	if stdout != want {
+		t.Errorf("output:\n%s\nwant:\n%s", stdout, want)
+	}
+}
+
+func TestInferCmd_fixGrouped(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/infer\n\ngo 1.22\n",
+		"shape.go": joinLines(
+			"package infer",
+			"",
+			"type Rect struct {",
+			"	X, Y int",
+			"	W, H int",
+			"}",
+			"",
+			"var rects = []Rect{",
+			"	{X: 1, W: 1, H: 1},",
+			"	{X: 2, W: 2, H: 2},",
+			"}",
+		),
+	})
+	t.Chdir(dir)
+
+	_, code, stderr := runInfer(t, "-min-literals", "2", "-fix", "./...")
+	if code != 0 {
+		t.Fatalf("exit code = %d, want 0:\n%s", code, stderr)
+	}
+
+	// X is suggested but Y isn't,
+	// so the comment can't be added to their declaration.
+	wantStderr := "requiredfield: example.com/infer.Rect.X is declared together with fields that aren't suggested\n"
+	if stderr != wantStderr {
+		t.Errorf("stderr:\n%s\nwant:\n%s", stderr, wantStderr)
+	}
+
+	got, err := os.ReadFile("shape.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := joinLines(
+		"package infer",
+		"",
+		"type Rect struct {",
+		"	X, Y int",
+		"	W, H int // required",
+		"}",
+		"",
+		"var rects = []Rect{",
+		"	{X: 1, W: 1, H: 1},",
+		"	{X: 2, W: 2, H: 2},",
+		"}",
+	)
+	if string(got) != want {
+		t.Errorf("shape.go:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func runInfer(t *testing.T, args ...string) (stdout string, exitCode int, stderr string) {
+	t.Helper()
+
+	var outBuf, errBuf bytes.Buffer
+	cmd := inferCmd{
+		Analyzer: requiredfield.New(requiredfield.Options{}),
+		Stdout:   &outBuf,
+		Stderr:   &errBuf,
+	}
+	exitCode = cmd.Run(args)
+	return outBuf.String(), exitCode, errBuf.String()
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"go.abhg.dev/requiredfield"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+)
+
+// listCmd implements the 'list' subcommand,
+// which prints all required fields of struct types
+// declared in the given packages,
+// and fields configured for other types.
+type listCmd struct {
+	Analyzer *analysis.Analyzer // required
+	Stdout   io.Writer          // required
+	Stderr   io.Writer          // required
+
+	format string
+	tests  bool
+}
+
+func (cmd *listCmd) registerFlags(fs *flag.FlagSet) {
+	cmd.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+
+	fs.StringVar(&cmd.format, "format", "text", "output format: text, json, or rc")
+	fs.BoolVar(&cmd.tests, "test", true, "indicates whether test files should be analyzed, too")
+}
+
+// Run runs the command with the given arguments
+// and returns the exit code.
+func (cmd *listCmd) Run(args []string) int {
+	fs := flag.NewFlagSet("requiredfield list", flag.ContinueOnError)
+	fs.SetOutput(cmd.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: requiredfield list [flags] packages...\n\n")
+		fmt.Fprintf(fs.Output(), "List required fields of struct types declared in the given packages.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	cmd.registerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 1
+	}
+
+	var write func(io.Writer, []*listedField) error
+	switch cmd.format {
+	case "text":
+		write = writeListText
+	case "json":
+		write = writeListJSON
+	case "rc":
+		write = writeListRC
+	default:
+		fmt.Fprintf(cmd.Stderr, "requiredfield: unknown format %q: must be one of text, json, rc\n", cmd.format)
+		return 1
+	}
+
+	fields, err := cmd.list(fs.Args())
+	if err != nil {
+		fmt.Fprintf(cmd.Stderr, "requiredfield: %v\n", err)
+		return 1
+	}
+
+	if err := write(cmd.Stdout, fields); err != nil {
+		fmt.Fprintf(cmd.Stderr, "requiredfield: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// listedField is a required field as reported by the list subcommand.
+type listedField struct {
+	Type        string `json:"type"`
+	Field       string `json:"field"`
+	Position    string `json:"position,omitempty"`
+	Description string `json:"description,omitempty"`
+	Origin      string `json:"origin,omitempty"`
+}
+
+// list runs the analyzer on the given packages
+// and returns all required fields it found, ordered by type.
+//
+// This includes fields configured for types
+// that aren't declared in the given packages.
+func (cmd *listCmd) list(patterns []string) ([]*listedField, error) {
+	pkgs, err := loadPackages(patterns, cmd.tests)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{cmd.Analyzer}, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		fields     []*listedField
+		configured []requiredfield.RequiredField
+		errs       []error
+	)
+	seen := make(map[listedField]struct{})
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", act.Package.PkgPath, act.Err))
+			continue
+		}
+
+		result := act.Result.(*requiredfield.RequiredFields)
+		configured = result.Configured // same for all packages
+		for _, f := range result.Fields {
+			lf := listedField{
+				Type:        f.Type,
+				Field:       f.Name,
+				Description: f.Description,
+				Origin:      relPath(wd, f.Origin),
+			}
+			if f.Pos.IsValid() {
+				pos := act.Package.Fset.Position(f.Pos)
+				pos.Filename = relPath(wd, pos.Filename)
+				lf.Position = pos.String()
+			}
+
+			// Test variants of a package report the same fields.
+			if _, ok := seen[lf]; ok {
+				continue
+			}
+			seen[lf] = struct{}{}
+			fields = append(fields, &lf)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	// Fields configured for types declared in the given packages
+	// are listed above with their positions.
+	// List the rest without.
+	declared := make(map[string]struct{})
+	for _, f := range fields {
+		declared[f.Type] = struct{}{}
+	}
+	for _, f := range configured {
+		if _, ok := declared[f.Type]; ok {
+			continue
+		}
+		fields = append(fields, &listedField{
+			Type:   f.Type,
+			Field:  f.Name,
+			Origin: relPath(wd, f.Origin),
+		})
+	}
+
+	// Keep fields of the same type in declaration order.
+	slices.SortStableFunc(fields, func(a, b *listedField) int {
+		return cmp.Compare(a.Type, b.Type)
+	})
+	return fields, nil
+}
+
+func writeListText(w io.Writer, fields []*listedField) error {
+	for _, f := range fields {
+		pos := f.Position
+		if pos == "" {
+			pos = "-"
+		}
+
+		line := fmt.Sprintf("%v: %v.%v", pos, f.Type, f.Field)
+		if f.Description != "" {
+			line += ": " + f.Description
+		}
+		if f.Origin != "" {
+			line += " (configured by " + f.Origin + ")"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeListJSON(w io.Writer, fields []*listedField) error {
+	if fields == nil {
+		fields = []*listedField{} // print [] instead of null
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(fields)
+}
+
+// writeListRC writes fields in the requiredfield.rc format
+// so that the output may be used as a configuration file
+// by consumers of the packages.
+func writeListRC(w io.Writer, fields []*listedField) error {
+	seen := make(map[string]struct{})
+	for _, f := range fields {
+		spec := f.Type + "." + f.Field
+		if _, ok := seen[spec]; ok {
+			continue
+		}
+		seen[spec] = struct{}{}
+
+		if f.Description != "" {
+			if _, err := fmt.Fprintf(w, "# %v\n", f.Description); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "required %v\n", spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relPath returns path relative to dir if it's inside dir,
+// and path unchanged otherwise.
+//
+// path may have a ":"-separated suffix, e.g. "foo.rc:3".
+func relPath(dir, path string) string {
+	if !filepath.IsAbs(path) {
+		return path
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"go.abhg.dev/requiredfield"
+)
+
+func TestListCmd(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/inventory\n\ngo 1.22\n",
+		"foo.go": joinLines(
+			"package foo",
+			"",
+			"type User struct {",
+			"	ID   string // required: must be unique",
+			"	Name string // required",
+			"	Age  int",
+			"",
+			"	Address struct {",
+			"		City string // required",
+			"	}",
+			"}",
+		),
+		"requiredfield.rc": "required example.com/inventory.User.Age\n",
+	})
+	t.Chdir(dir)
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "text",
+			format: "text",
+			want: joinLines(
+				"foo.go:4:2: example.com/inventory.User.ID: must be unique",
+				"foo.go:5:2: example.com/inventory.User.Name",
+				"foo.go:6:2: example.com/inventory.User.Age (configured by requiredfield.rc:1)",
+				"foo.go:9:3: example.com/inventory.User.Address.City",
+			),
+		},
+		{
+			name:   "rc",
+			format: "rc",
+			want: joinLines(
+				"# must be unique",
+				"required example.com/inventory.User.ID",
+				"required example.com/inventory.User.Name",
+				"required example.com/inventory.User.Age",
+				"required example.com/inventory.User.Address.City",
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, code, stderr := runList(t, "-config", "requiredfield.rc", "-format", tt.format, "./...")
+			if code != 0 {
+				t.Fatalf("exit code = %d, want 0:\n%s", code, stderr)
+			}
+			if stdout != tt.want {
+				t.Errorf("output:\n%s\nwant:\n%s", stdout, tt.want)
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		stdout, code, stderr := runList(t, "-config", "requiredfield.rc", "-format", "json", "./...")
+		if code != 0 {
+			t.Fatalf("exit code = %d, want 0:\n%s", code, stderr)
+		}
+
+		var got []listedField
+		if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+		}
+		want := []listedField{
+			{Type: "example.com/inventory.User", Field: "ID", Position: "foo.go:4:2", Description: "must be unique"},
+			{Type: "example.com/inventory.User", Field: "Name", Position: "foo.go:5:2"},
+			{Type: "example.com/inventory.User", Field: "Age", Position: "foo.go:6:2", Origin: "requiredfield.rc:1"},
+			{Type: "example.com/inventory.User.Address", Field: "City", Position: "foo.go:9:3"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("other packages", func(t *testing.T) {
+		stdout, code, stderr := runList(t, "-required", "net/http.Request.Method", "./...")
+		if code != 0 {
+			t.Fatalf("exit code = %d, want 0:\n%s", code, stderr)
+		}
+
+		want := joinLines(
+			"foo.go:4:2: example.com/inventory.User.ID: must be unique",
+			"foo.go:5:2: example.com/inventory.User.Name",
+			"foo.go:9:3: example.com/inventory.User.Address.City",
+			"-: net/http.Request.Method (configured by -required)",
+		)
+		if stdout != want {
+			t.Errorf("output:\n%s\nwant:\n%s", stdout, want)
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, code, stderr := runList(t, "-format", "yaml", "./...")
+		if code != 1 {
+			t.Errorf("exit code = %d, want 1", code)
+		}
+		if want := `unknown format "yaml"`; !bytes.Contains([]byte(stderr), []byte(want)) {
+			t.Errorf("stderr = %q, want %q", stderr, want)
+		}
+	})
+}
+
+func runList(t *testing.T, args ...string) (stdout string, exitCode int, stderr string) {
+	t.Helper()
+
+	var outBuf, errBuf bytes.Buffer
+	cmd := listCmd{
+		Analyzer: requiredfield.New(requiredfield.Options{}),
+		Stdout:   &outBuf,
+		Stderr:   &errBuf,
+	}
+	exitCode = cmd.Run(args)
+	return outBuf.String(), exitCode, errBuf.String()
+}
@@ -12,9 +12,73 @@
 //
 //	$ go vet -vettool=$(which requiredfield) ./...
 //
+// # Incremental adoption
+//
+// To adopt a new required field in a large codebase
+// without fixing all existing violations at once,
+// record current findings in a baseline file:
+//
+//	$ requiredfield -write-baseline=requiredfield.baseline.json ./...
+//
+// Then pass it to future runs to report only new findings:
+//
+//	$ requiredfield -baseline=requiredfield.baseline.json ./...
+//
+// To report only struct literals changed on the current branch,
+// pass the revision it was branched from:
+//
+//	$ requiredfield -diff-base=origin/main ./...
+//
+// Alternatively, pass a unified diff with -diff, or '-diff=-' for stdin.
+//
+// Baselines and diffs are not supported with 'go vet'.
+//
+// # Reports
+//
+// To print findings as a SARIF 2.1.0 log or as Checkstyle XML,
+// pass -format=sarif or -format=checkstyle:
+//
+//	$ requiredfield -format=sarif ./... > requiredfield.sarif
+//
+// # Listing required fields
+//
+// To list required fields of struct types declared in a set of packages,
+// use the 'list' subcommand:
+//
+//	$ requiredfield list ./...
+//
+// Pass -format=json for machine-readable output,
+// or -format=rc to generate a requiredfield.rc file
+// that consumers of the packages may use with -config.
+//
+// # Inferring required fields
+//
+// To find fields that are set in almost every literal of their type
+// and are therefore likely to be required, use the 'infer' subcommand:
+//
+//	$ requiredfield infer ./...
+//
+// Pass -format=rc to print suggestions as a requiredfield.rc file,
+// or -fix to add '// required' comments to their declarations.
+//
+// # Exporting JSON Schema
+//
+// To keep documentation and validation in sync with required fields,
+// print a JSON Schema document for struct types
+// with the 'schema' subcommand:
+//
+//	$ requiredfield schema -type=User -type=Order ./api
+//
+// Required fields become the "required" properties of each schema,
+// named as they are in JSON.
+// Without -type, all exported struct types with required fields are included.
+//
 // # As a golangci-lint plugin
 //
-// Build the plugin:
+// To build requiredfield into a custom golangci-lint binary,
+// use the go.abhg.dev/requiredfield/golangci module plugin.
+//
+// Alternatively, build a Go plugin:
 //
 //	$ go build -buildmode=plugin go.abhg.dev/requiredfield/cmd/requiredfield
 //
@@ -30,13 +94,60 @@
 package main
 
 import (
+	"os"
+
 	"go.abhg.dev/requiredfield"
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/singlechecker"
 )
 
 func main() {
-	singlechecker.Main(requiredfield.Analyzer)
+	// Commands set flags on the analyzer,
+	// so don't use the shared requiredfield.Analyzer.
+	analyzer := requiredfield.New(requiredfield.Options{})
+
+	args := os.Args[1:]
+	if len(args) > 0 {
+		switch args[0] {
+		case "list":
+			cmd := listCmd{
+				Analyzer: analyzer,
+				Stdout:   os.Stdout,
+				Stderr:   os.Stderr,
+			}
+			os.Exit(cmd.Run(args[1:]))
+
+		case "infer":
+			cmd := inferCmd{
+				Analyzer: analyzer,
+				Stdout:   os.Stdout,
+				Stderr:   os.Stderr,
+			}
+			os.Exit(cmd.Run(args[1:]))
+
+		case "schema":
+			cmd := schemaCmd{
+				Analyzer: analyzer,
+				Stdout:   os.Stdout,
+				Stderr:   os.Stderr,
+			}
+			os.Exit(cmd.Run(args[1:]))
+		}
+	}
+
+	cmd := checkCmd{
+		Analyzer: analyzer,
+		Stdin:    os.Stdin,
+		Stdout:   os.Stdout,
+		Stderr:   os.Stderr,
+	}
+	if !cmd.Handles(args) {
+		// singlechecker implements the 'go vet' protocol
+		// and flags that checkCmd doesn't support.
+		singlechecker.Main(analyzer)
+		return
+	}
+	os.Exit(cmd.Run(args))
 }
 
 // AnalyzerPlugin provides the analyzer as a golangci-lint plugin.
@@ -0,0 +1,415 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"go/token"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.abhg.dev/requiredfield"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+)
+
+// _reportFormats lists the formats supported by the -format flag
+// in addition to the default "text".
+var _reportFormats = []string{"sarif", "checkstyle"}
+
+// report holds findings to write in a machine-readable format.
+type report struct {
+	Findings []*finding // required
+
+	// Dir is the directory that paths are reported relative to.
+	Dir string // required
+
+	// Fields maps "package/path.Type.Field"
+	// to the declaration of that required field, if known.
+	Fields map[string]*declaredField
+}
+
+// declaredField is a required field with a known declaration.
+type declaredField struct {
+	Position    token.Position
+	Description string
+}
+
+// newReport builds a report for the root findings of the graph.
+// dir is the directory that paths are reported relative to.
+func newReport(graph *checker.Graph, dir string) *report {
+	fields := make(map[string]*declaredField)
+	for act := range graph.All() {
+		result, ok := act.Result.(*requiredfield.RequiredFields)
+		if !ok || act.Package == nil {
+			continue
+		}
+
+		for _, f := range result.Fields {
+			key := f.Type + "." + f.Name
+			if _, ok := fields[key]; ok || !f.Pos.IsValid() {
+				continue
+			}
+			fields[key] = &declaredField{
+				Position:    act.Package.Fset.Position(f.Pos),
+				Description: f.Description,
+			}
+		}
+	}
+
+	findings := rootFindings(graph)
+	slices.SortStableFunc(findings, func(a, b *finding) int {
+		return cmp.Or(
+			cmp.Compare(a.Position.Filename, b.Position.Filename),
+			cmp.Compare(a.Position.Line, b.Position.Line),
+			cmp.Compare(a.Position.Column, b.Position.Column),
+		)
+	})
+
+	return &report{
+		Findings: findings,
+		Dir:      dir,
+		Fields:   fields,
+	}
+}
+
+// missingFields returns declarations of the required fields
+// that a finding reports as missing, in the order they're reported.
+// Fields without a known declaration are skipped.
+func (r *report) missingFields(f *finding) (names []string, decls []*declaredField) {
+	entry := newBaselineEntry(f)
+	if entry.Type == "" {
+		return nil, nil
+	}
+
+	// Fields marked with comments on generic types
+	// are listed for the generic type, e.g. "Page" for "Page[int]".
+	origin, _, _ := strings.Cut(entry.Type, "[")
+	for _, name := range entry.Missing {
+		decl, ok := r.Fields[entry.Type+"."+name]
+		if !ok {
+			decl, ok = r.Fields[origin+"."+name]
+		}
+		if ok {
+			names = append(names, name)
+			decls = append(decls, decl)
+		}
+	}
+	return names, decls
+}
+
+// path returns the path of a file relative to the report directory
+// using forward slashes.
+// Files outside the directory are reported with absolute paths.
+func (r *report) path(filename string) string {
+	return filepath.ToSlash(relPath(r.Dir, filename))
+}
+
+// SARIF 2.1.0 log format.
+// Only the subset of the format used by requiredfield is defined here.
+//
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+type (
+	sarifLog struct {
+		Schema  string      `json:"$schema"`
+		Version string      `json:"version"`
+		Runs    []*sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool               sarifTool                    `json:"tool"`
+		OriginalURIBaseIDs map[string]*sarifArtifactLoc `json:"originalUriBaseIds,omitempty"`
+		Results            []*sarifResult               `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string       `json:"name"`
+		InformationURI string       `json:"informationUri"`
+		Rules          []*sarifRule `json:"rules"`
+	}
+
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifResult struct {
+		RuleID           string           `json:"ruleId"`
+		RuleIndex        int              `json:"ruleIndex"`
+		Level            string           `json:"level"`
+		Message          sarifMessage     `json:"message"`
+		Locations        []*sarifLocation `json:"locations"`
+		RelatedLocations []*sarifLocation `json:"relatedLocations,omitempty"`
+		Fixes            []*sarifFix      `json:"fixes,omitempty"`
+	}
+
+	sarifLocation struct {
+		ID               *int                  `json:"id,omitempty"`
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+		Message          *sarifMessage         `json:"message,omitempty"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation *sarifArtifactLoc `json:"artifactLocation"`
+		Region           *sarifRegion      `json:"region"`
+	}
+
+	sarifArtifactLoc struct {
+		URI       string `json:"uri"`
+		URIBaseID string `json:"uriBaseId,omitempty"`
+	}
+
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn,omitempty"`
+		EndLine     int `json:"endLine,omitempty"`
+		EndColumn   int `json:"endColumn,omitempty"`
+	}
+
+	sarifFix struct {
+		Description     sarifMessage           `json:"description"`
+		ArtifactChanges []*sarifArtifactChange `json:"artifactChanges"`
+	}
+
+	sarifArtifactChange struct {
+		ArtifactLocation *sarifArtifactLoc   `json:"artifactLocation"`
+		Replacements     []*sarifReplacement `json:"replacements"`
+	}
+
+	sarifReplacement struct {
+		DeletedRegion   *sarifRegion  `json:"deletedRegion"`
+		InsertedContent *sarifMessage `json:"insertedContent,omitempty"`
+	}
+)
+
+// _sarifSrcRoot is the URI base ID for paths relative to the report directory.
+const _sarifSrcRoot = "SRCROOT"
+
+// WriteSARIF writes the report in the SARIF 2.1.0 format.
+//
+// Results include the declarations of missing required fields
+// as related locations, and suggested fixes, if any.
+func (r *report) WriteSARIF(w io.Writer, name string) error {
+	run := &sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           name,
+				InformationURI: "https://go.abhg.dev/requiredfield",
+				Rules:          []*sarifRule{},
+			},
+		},
+		OriginalURIBaseIDs: map[string]*sarifArtifactLoc{
+			_sarifSrcRoot: {URI: fileURI(r.Dir) + "/"},
+		},
+		Results: []*sarifResult{},
+	}
+
+	ruleIndex := make(map[string]int)
+	for _, f := range r.Findings {
+		id, desc := requiredfield.DiagnosticCategory(f.Diagnostic)
+		idx, ok := ruleIndex[id]
+		if !ok {
+			idx = len(run.Tool.Driver.Rules)
+			ruleIndex[id] = idx
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, &sarifRule{
+				ID:               id,
+				ShortDescription: sarifMessage{Text: desc},
+			})
+		}
+
+		level, msg := requiredfield.DiagnosticSeverity(f.Diagnostic)
+		if level == "info" {
+			level = "note"
+		}
+
+		fset := f.Package.Fset
+		result := &sarifResult{
+			RuleID:    id,
+			RuleIndex: idx,
+			Level:     level,
+			Message:   sarifMessage{Text: msg},
+			Locations: []*sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: r.sarifArtifact(f.Position.Filename),
+						Region:           sarifRange(fset, f.Diagnostic.Pos, f.Diagnostic.End),
+					},
+				},
+			},
+		}
+
+		names, decls := r.missingFields(f)
+		for i, decl := range decls {
+			text := names[i] + " is required"
+			if decl.Description != "" {
+				text += ": " + decl.Description
+			}
+
+			result.RelatedLocations = append(result.RelatedLocations, &sarifLocation{
+				ID: &i,
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: r.sarifArtifact(decl.Position.Filename),
+					Region: &sarifRegion{
+						StartLine:   decl.Position.Line,
+						StartColumn: decl.Position.Column,
+					},
+				},
+				Message: &sarifMessage{Text: text},
+			})
+		}
+
+		for _, fix := range f.Diagnostic.SuggestedFixes {
+			result.Fixes = append(result.Fixes, r.sarifFix(fset, fix))
+		}
+
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []*sarifRun{run},
+	})
+}
+
+func (r *report) sarifArtifact(filename string) *sarifArtifactLoc {
+	path := r.path(filename)
+	if filepath.IsAbs(filename) && path == filepath.ToSlash(filename) {
+		// Outside the report directory.
+		return &sarifArtifactLoc{URI: fileURI(filename)}
+	}
+	return &sarifArtifactLoc{URI: path, URIBaseID: _sarifSrcRoot}
+}
+
+// sarifFix converts a suggested fix to SARIF,
+// grouping edits by the file they apply to.
+func (r *report) sarifFix(fset *token.FileSet, fix analysis.SuggestedFix) *sarifFix {
+	var (
+		changes []*sarifArtifactChange
+		byFile  = make(map[string]*sarifArtifactChange)
+	)
+	for _, edit := range fix.TextEdits {
+		filename := fset.Position(edit.Pos).Filename
+		change, ok := byFile[filename]
+		if !ok {
+			change = &sarifArtifactChange{ArtifactLocation: r.sarifArtifact(filename)}
+			byFile[filename] = change
+			changes = append(changes, change)
+		}
+
+		repl := &sarifReplacement{
+			DeletedRegion: sarifRange(fset, edit.Pos, cmp.Or(edit.End, edit.Pos)),
+		}
+		if len(edit.NewText) > 0 {
+			repl.InsertedContent = &sarifMessage{Text: string(edit.NewText)}
+		}
+		change.Replacements = append(change.Replacements, repl)
+	}
+
+	return &sarifFix{
+		Description:     sarifMessage{Text: fix.Message},
+		ArtifactChanges: changes,
+	}
+}
+
+// sarifRange returns the region between two positions.
+// end may be token.NoPos.
+func sarifRange(fset *token.FileSet, pos, end token.Pos) *sarifRegion {
+	start := fset.Position(pos)
+	region := &sarifRegion{
+		StartLine:   start.Line,
+		StartColumn: start.Column,
+	}
+	if end.IsValid() {
+		end := fset.Position(end)
+		region.EndLine = end.Line
+		region.EndColumn = end.Column
+	}
+	return region
+}
+
+// fileURI returns a file:// URI for an absolute path.
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // Windows drive letters
+	}
+	return "file://" + path
+}
+
+// Checkstyle XML format.
+type (
+	checkstyleLog struct {
+		XMLName xml.Name          `xml:"checkstyle"`
+		Version string            `xml:"version,attr"`
+		Files   []*checkstyleFile `xml:"file"`
+	}
+
+	checkstyleFile struct {
+		Name   string             `xml:"name,attr"`
+		Errors []*checkstyleError `xml:"error"`
+	}
+
+	checkstyleError struct {
+		Line     int    `xml:"line,attr"`
+		Column   int    `xml:"column,attr,omitempty"`
+		Severity string `xml:"severity,attr"`
+		Message  string `xml:"message,attr"`
+		Source   string `xml:"source,attr"`
+	}
+)
+
+// WriteCheckstyle writes the report in the Checkstyle XML format,
+// grouping findings by file.
+func (r *report) WriteCheckstyle(w io.Writer, name string) error {
+	log := checkstyleLog{Version: "5.0"}
+
+	byFile := make(map[string]*checkstyleFile)
+	for _, f := range r.Findings {
+		path := r.path(f.Position.Filename)
+		file, ok := byFile[path]
+		if !ok {
+			file = &checkstyleFile{Name: path}
+			byFile[path] = file
+			log.Files = append(log.Files, file)
+		}
+
+		severity, msg := requiredfield.DiagnosticSeverity(f.Diagnostic)
+		category, _ := requiredfield.DiagnosticCategory(f.Diagnostic)
+		file.Errors = append(file.Errors, &checkstyleError{
+			Line:     f.Position.Line,
+			Column:   f.Position.Column,
+			Severity: severity,
+			Message:  msg,
+			Source:   name + "." + category,
+		})
+	}
+
+	slices.SortStableFunc(log.Files, func(a, b *checkstyleFile) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(&log); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
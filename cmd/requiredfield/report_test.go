@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestCheckCmd_format(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/report\n\ngo 1.22\n",
+		"foo.go": joinLines(
+			"package foo",
+			"",
+			"type User struct {",
+			"	ID   string // required: must be unique",
+			"	Name string",
+			"}",
+			"",
+			"func NewUser() User {",
+			"	return User{Name: \"x\"}",
+			"}",
+		),
+	})
+	t.Chdir(dir)
+
+	t.Run("sarif", func(t *testing.T) {
+		stdout, code, stderr := runCheck(t, "-format=sarif", "./...")
+		if code != 0 {
+			t.Fatalf("exit code = %d, want 0:\n%s", code, stderr)
+		}
+
+		var log sarifLog
+		if err := json.Unmarshal([]byte(stdout), &log); err != nil {
+			t.Fatalf("invalid SARIF: %v\n%s", err, stdout)
+		}
+		if log.Version != "2.1.0" || len(log.Runs) != 1 {
+			t.Fatalf("unexpected SARIF log:\n%s", stdout)
+		}
+
+		run := log.Runs[0]
+		if len(run.Tool.Driver.Rules) != 1 || run.Tool.Driver.Rules[0].ID != "missing-required" {
+			t.Errorf("rules = %+v, want missing-required", run.Tool.Driver.Rules)
+		}
+		if len(run.Results) != 1 {
+			t.Fatalf("got %d results, want 1:\n%s", len(run.Results), stdout)
+		}
+
+		result := run.Results[0]
+		if result.Level != "error" || result.Message.Text != "missing required fields: ID" {
+			t.Errorf("result = %v: %q, want error: missing required fields: ID", result.Level, result.Message.Text)
+		}
+
+		loc := result.Locations[0].PhysicalLocation
+		if loc.ArtifactLocation.URI != "foo.go" || loc.Region.StartLine != 9 {
+			t.Errorf("location = %v:%v, want foo.go:9", loc.ArtifactLocation.URI, loc.Region.StartLine)
+		}
+
+		if len(result.RelatedLocations) != 1 {
+			t.Fatalf("got %d related locations, want 1:\n%s", len(result.RelatedLocations), stdout)
+		}
+		related := result.RelatedLocations[0]
+		if got, want := related.Message.Text, "ID is required: must be unique"; got != want {
+			t.Errorf("related message = %q, want %q", got, want)
+		}
+		if got := related.PhysicalLocation.Region.StartLine; got != 4 {
+			t.Errorf("related line = %v, want 4", got)
+		}
+	})
+
+	t.Run("sarif fixes", func(t *testing.T) {
+		stdout, code, stderr := runCheck(t, "-format=sarif", "-promote", "./...")
+		if code != 0 {
+			t.Fatalf("exit code = %d, want 0:\n%s", code, stderr)
+		}
+
+		var log sarifLog
+		if err := json.Unmarshal([]byte(stdout), &log); err != nil {
+			t.Fatalf("invalid SARIF: %v\n%s", err, stdout)
+		}
+
+		var found bool
+		for _, result := range log.Runs[0].Results {
+			if result.RuleID != "promote" {
+				continue
+			}
+			found = true
+
+			if result.Level != "note" {
+				t.Errorf("level = %q, want note", result.Level)
+			}
+			if len(result.Fixes) != 1 || result.Fixes[0].Description.Text != "Mark Name as required" {
+				t.Fatalf("unexpected fixes:\n%s", stdout)
+			}
+
+			var inserted []string
+			for _, change := range result.Fixes[0].ArtifactChanges {
+				for _, repl := range change.Replacements {
+					inserted = append(inserted, repl.InsertedContent.Text)
+				}
+			}
+			if !strings.Contains(strings.Join(inserted, "|"), "// required") {
+				t.Errorf("fix does not add a required comment: %q", inserted)
+			}
+		}
+		if !found {
+			t.Errorf("promote result not reported:\n%s", stdout)
+		}
+	})
+
+	t.Run("checkstyle", func(t *testing.T) {
+		stdout, code, stderr := runCheck(t, "-format=checkstyle", "./...")
+		if code != 0 {
+			t.Fatalf("exit code = %d, want 0:\n%s", code, stderr)
+		}
+
+		var log checkstyleLog
+		if err := xml.Unmarshal([]byte(stdout), &log); err != nil {
+			t.Fatalf("invalid Checkstyle XML: %v\n%s", err, stdout)
+		}
+		if len(log.Files) != 1 || log.Files[0].Name != "foo.go" {
+			t.Fatalf("unexpected files:\n%s", stdout)
+		}
+
+		errs := log.Files[0].Errors
+		if len(errs) != 1 {
+			t.Fatalf("got %d errors, want 1:\n%s", len(errs), stdout)
+		}
+		want := checkstyleError{
+			Line:     9,
+			Column:   13,
+			Severity: "error",
+			Message:  "missing required fields: ID",
+			Source:   "requiredfield.missing-required",
+		}
+		if *errs[0] != want {
+			t.Errorf("error = %+v, want %+v", *errs[0], want)
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, code, stderr := runCheck(t, "-format=html", "./...")
+		if code != 1 || !strings.Contains(stderr, `unknown format "html"`) {
+			t.Errorf("exit code = %d, want 1 with unknown format error:\n%s", code, stderr)
+		}
+	})
+
+	t.Run("json and format", func(t *testing.T) {
+		_, code, stderr := runCheck(t, "-json", "-format=sarif", "./...")
+		if code != 1 || !strings.Contains(stderr, "cannot be used together") {
+			t.Errorf("exit code = %d, want 1 with conflict error:\n%s", code, stderr)
+		}
+	})
+}
@@ -0,0 +1,438 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/types"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"go.abhg.dev/requiredfield"
+	"go.abhg.dev/requiredfield/internal/jsonschema"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// _jsonSchemaDialect is the JSON Schema version of generated documents.
+const _jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaCmd implements the 'schema' subcommand,
+// which prints a JSON Schema document for struct types
+// declared in the given packages.
+type schemaCmd struct {
+	Analyzer *analysis.Analyzer // required
+	Stdout   io.Writer          // required
+	Stderr   io.Writer          // required
+
+	types []string
+	tests bool
+}
+
+func (cmd *schemaCmd) registerFlags(fs *flag.FlagSet) {
+	cmd.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+
+	fs.Func("type",
+		"generate a schema for the struct type in the form 'Type' or 'package/path.Type' (may be repeated)",
+		func(s string) error {
+			cmd.types = append(cmd.types, s)
+			return nil
+		})
+	fs.BoolVar(&cmd.tests, "test", true, "indicates whether test files should be analyzed, too")
+}
+
+// Run runs the command with the given arguments
+// and returns the exit code.
+func (cmd *schemaCmd) Run(args []string) int {
+	fs := flag.NewFlagSet("requiredfield schema", flag.ContinueOnError)
+	fs.SetOutput(cmd.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: requiredfield schema [flags] packages...\n\n")
+		fmt.Fprintf(fs.Output(), "Print a JSON Schema document for struct types declared in the given packages.\n")
+		fmt.Fprintf(fs.Output(), "Without -type, all exported struct types with required fields are included.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	cmd.registerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 1
+	}
+
+	doc, err := cmd.schema(fs.Args())
+	if err != nil {
+		fmt.Fprintf(cmd.Stderr, "requiredfield: %v\n", err)
+		return 1
+	}
+
+	enc := json.NewEncoder(cmd.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		fmt.Fprintf(cmd.Stderr, "requiredfield: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// schemaDocument is a JSON Schema document
+// with a definition for each struct type.
+type schemaDocument struct {
+	Schema string                 `json:"$schema"`
+	Defs   map[string]*jsonSchema `json:"$defs"` // keyed by "package/path.Type"
+}
+
+// jsonSchema is the subset of JSON Schema
+// needed to describe Go types as encoding/json sees them.
+type jsonSchema struct {
+	Ref                  string           `json:"$ref,omitempty"`
+	Type                 string           `json:"type,omitempty"`
+	Format               string           `json:"format,omitempty"`
+	ContentEncoding      string           `json:"contentEncoding,omitempty"`
+	Description          string           `json:"description,omitempty"`
+	Items                *jsonSchema      `json:"items,omitempty"`
+	Properties           schemaProperties `json:"properties,omitempty"`
+	AdditionalProperties *jsonSchema      `json:"additionalProperties,omitempty"`
+	Required             []string         `json:"required,omitempty"`
+}
+
+// schemaProperties are the properties of an object schema
+// in field declaration order.
+type schemaProperties []*schemaProperty
+
+type schemaProperty struct {
+	Name   string      // required
+	Schema *jsonSchema // required
+}
+
+func (props schemaProperties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, p := range props {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(p.Name)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := json.Marshal(p.Schema)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(schema)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// schema runs the analyzer on the given packages
+// and builds a JSON Schema document for the selected types.
+func (cmd *schemaCmd) schema(patterns []string) (*schemaDocument, error) {
+	pkgs, err := loadPackages(patterns, cmd.tests)
+	if err != nil {
+		return nil, err
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		return nil, errors.New("packages contain errors")
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{cmd.Analyzer}, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	gen := schemaGenerator{
+		defs:         make(map[string]*jsonSchema),
+		descriptions: make(map[string]string),
+	}
+
+	// Root packages declaring the requested types,
+	// in the order they were loaded.
+	type rootType struct {
+		Named  *types.Named
+		Result *requiredfield.RequiredFields
+	}
+	var (
+		roots []rootType
+		errs  []error
+	)
+	seen := make(map[string]struct{}) // "package/path.Type"
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", act.Package.PkgPath, act.Err))
+			continue
+		}
+
+		result := act.Result.(*requiredfield.RequiredFields)
+		for _, f := range result.Fields {
+			if f.Description != "" {
+				gen.descriptions[f.Type+"."+f.Name] = f.Description
+			}
+		}
+
+		scope := act.Package.Types.Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || tn.IsAlias() {
+				continue
+			}
+			named, ok := tn.Type().(*types.Named)
+			if !ok || named.TypeParams().Len() > 0 {
+				continue // generic types must be instantiated
+			}
+			if _, ok := named.Underlying().(*types.Struct); !ok {
+				continue
+			}
+
+			// Test variants of a package declare the same types.
+			key := types.TypeString(named, nil)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			roots = append(roots, rootType{Named: named, Result: result})
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	if len(cmd.types) == 0 {
+		for _, rt := range roots {
+			if rt.Named.Obj().Exported() && len(rt.Result.Required(rt.Named)) > 0 {
+				gen.define(rt.Result, rt.Named)
+			}
+		}
+	} else {
+		for _, want := range cmd.types {
+			var found bool
+			for _, rt := range roots {
+				obj := rt.Named.Obj()
+				if want == obj.Name() || want == obj.Pkg().Path()+"."+obj.Name() {
+					gen.define(rt.Result, rt.Named)
+					found = true
+				}
+			}
+			if !found {
+				errs = append(errs, fmt.Errorf("type %q: no struct type found in the given packages", want))
+			}
+		}
+		if err := errors.Join(errs...); err != nil {
+			return nil, err
+		}
+	}
+
+	return &schemaDocument{
+		Schema: _jsonSchemaDialect,
+		Defs:   gen.defs,
+	}, nil
+}
+
+// schemaGenerator converts Go types to JSON Schema
+// following the rules of encoding/json.
+type schemaGenerator struct {
+	// defs holds a definition for each named struct type
+	// referenced by the generated schemas.
+	defs map[string]*jsonSchema
+
+	// descriptions maps "package/path.Type.Field"
+	// to the description of the required field.
+	descriptions map[string]string
+}
+
+// define adds a definition for a named struct type
+// and returns a reference to it.
+func (g *schemaGenerator) define(r *requiredfield.RequiredFields, named *types.Named) *jsonSchema {
+	key := types.TypeString(named, nil)
+	ref := &jsonSchema{Ref: "#/$defs/" + jsonschema.EscapePointer(key)}
+	if _, ok := g.defs[key]; ok {
+		return ref
+	}
+
+	// Register before generating the definition
+	// so that recursive types refer to themselves.
+	def := &jsonSchema{Type: "object"}
+	g.defs[key] = def
+	g.fillObject(r, def, key, named, named.Underlying().(*types.Struct))
+	return ref
+}
+
+// fillObject adds the properties of a struct type to an object schema.
+// typ identifies the struct type for required field descriptions.
+func (g *schemaGenerator) fillObject(r *requiredfield.RequiredFields, obj *jsonSchema, key string, typ types.Type, st *types.Struct) {
+	required := r.Required(typ)
+
+	var embedded []*types.Var
+	for i := range st.NumFields() {
+		field := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+
+		name, ok := jsonschema.FieldName(field, st.Tag(i))
+		if !ok {
+			continue
+		}
+		if name == "" {
+			// Fields of embedded structs without a JSON name
+			// are promoted to the outer object.
+			embedded = append(embedded, field)
+			continue
+		}
+		if obj.Properties.has(name) {
+			continue
+		}
+
+		schema := g.schemaOf(r, key+"."+field.Name(), field.Type())
+		if _, opts, _ := strings.Cut(tag.Get("json"), ","); hasOption(opts, "string") && isScalar(field.Type()) {
+			schema = &jsonSchema{Type: "string"}
+		}
+		if desc := g.descriptions[key+"."+field.Name()]; desc != "" {
+			schema.Description = desc
+		}
+		obj.Properties = append(obj.Properties, &schemaProperty{Name: name, Schema: schema})
+
+		if slices.Contains(required, field.Name()) {
+			obj.Required = append(obj.Required, name)
+		}
+	}
+
+	// Fields declared directly on the struct take precedence
+	// over those promoted from embedded structs.
+	for _, field := range embedded {
+		typ := field.Type()
+		if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+			typ = ptr.Elem()
+		}
+		est, ok := typ.Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+
+		ekey := key + "." + field.Name()
+		if named, ok := types.Unalias(typ).(*types.Named); ok {
+			ekey = types.TypeString(named, nil)
+		}
+
+		var promoted jsonSchema
+		g.fillObject(r, &promoted, ekey, typ, est)
+		for _, p := range promoted.Properties {
+			if obj.Properties.has(p.Name) {
+				continue
+			}
+			obj.Properties = append(obj.Properties, p)
+			if slices.Contains(promoted.Required, p.Name) {
+				obj.Required = append(obj.Required, p.Name)
+			}
+		}
+	}
+}
+
+func (props schemaProperties) has(name string) bool {
+	return slices.ContainsFunc(props, func(p *schemaProperty) bool {
+		return p.Name == name
+	})
+}
+
+// schemaOf returns the schema for values of the given type.
+// key identifies the enclosing field for nested anonymous structs.
+func (g *schemaGenerator) schemaOf(r *requiredfield.RequiredFields, key string, typ types.Type) *jsonSchema {
+	typ = types.Unalias(typ)
+	if ptr, ok := typ.(*types.Pointer); ok {
+		return g.schemaOf(r, key, ptr.Elem())
+	}
+
+	if named, ok := typ.(*types.Named); ok {
+		obj := named.Obj()
+		switch {
+		case obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time":
+			return &jsonSchema{Type: "string", Format: "date-time"}
+		case hasMethod(named, "MarshalJSON"):
+			return &jsonSchema{} // any value
+		case hasMethod(named, "MarshalText"):
+			return &jsonSchema{Type: "string"}
+		}
+		if _, ok := named.Underlying().(*types.Struct); ok {
+			return g.define(r, named)
+		}
+	}
+
+	switch u := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return &jsonSchema{Type: "boolean"}
+		case u.Info()&types.IsInteger != 0:
+			return &jsonSchema{Type: "integer"}
+		case u.Info()&types.IsFloat != 0:
+			return &jsonSchema{Type: "number"}
+		case u.Info()&types.IsString != 0:
+			return &jsonSchema{Type: "string"}
+		}
+
+	case *types.Slice:
+		if isByte(u.Elem()) {
+			return &jsonSchema{Type: "string", ContentEncoding: "base64"}
+		}
+		return &jsonSchema{Type: "array", Items: g.schemaOf(r, key, u.Elem())}
+
+	case *types.Array:
+		return &jsonSchema{Type: "array", Items: g.schemaOf(r, key, u.Elem())}
+
+	case *types.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: g.schemaOf(r, key, u.Elem())}
+
+	case *types.Struct:
+		obj := &jsonSchema{Type: "object"}
+		g.fillObject(r, obj, key, typ, u)
+		return obj
+	}
+
+	// Interfaces and anything else may hold any value.
+	return &jsonSchema{}
+}
+
+// hasOption reports whether a comma-separated list of options
+// includes the given option.
+func hasOption(opts, opt string) bool {
+	for o := range strings.SplitSeq(opts, ",") {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// isScalar reports whether the ",string" option applies to the type.
+func isScalar(typ types.Type) bool {
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	b, ok := typ.Underlying().(*types.Basic)
+	return ok && b.Info()&(types.IsBoolean|types.IsNumeric|types.IsString) != 0
+}
+
+func isByte(typ types.Type) bool {
+	b, ok := typ.Underlying().(*types.Basic)
+	return ok && b.Kind() == types.Byte
+}
+
+// hasMethod reports whether the type or a pointer to it
+// has a method with the given name.
+func hasMethod(typ types.Type, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(typ), true, nil, name)
+	_, ok := obj.(*types.Func)
+	return ok
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.abhg.dev/requiredfield"
+)
+
+func TestSchemaCmd(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/schema\n\ngo 1.22\n",
+		"api.go": joinLines(
+			"package api",
+			"",
+			"import \"time\"",
+			"",
+			"type Base struct {",
+			"	ID string `json:\"id\"` // required",
+			"}",
+			"",
+			"type User struct {",
+			"	Base",
+			"	Name    string    `json:\"name\"` // required: display name",
+			"	Email   string    `json:\"email,omitempty\"`",
+			"	Age     int       `json:\"age,string\"`",
+			"	Created time.Time `json:\"created\"`",
+			"	Secret  string    `json:\"-\"` // required",
+			"	Avatar  []byte",
+			"	Friends []*User   `json:\"friends\"`",
+			"	Labels  map[string]string",
+			"	hidden  bool",
+			"",
+			"	Address struct {",
+			"		City string `json:\"city\"` // required",
+			"	} `json:\"address\"`",
+			"}",
+			"",
+			"type Order struct {",
+			"	Total float64",
+			"	Owner *User",
+			"}",
+		),
+		"requiredfield.rc": "required example.com/schema.Order.Total\n",
+	})
+	t.Chdir(dir)
+
+	t.Run("all", func(t *testing.T) {
+		stdout, code, stderr := runSchema(t, "-config", "requiredfield.rc", "./...")
+		if code != 0 {
+			t.Fatalf("exit code = %d, want 0:\n%s", code, stderr)
+		}
+
+		want := joinLines(
+			`{`,
+			`  "$schema": "https://json-schema.org/draft/2020-12/schema",`,
+			`  "$defs": {`,
+			`    "example.com/schema.Base": {`,
+			`      "type": "object",`,
+			`      "properties": {`,
+			`        "id": {`,
+			`          "type": "string"`,
+			`        }`,
+			`      },`,
+			`      "required": [`,
+			`        "id"`,
+			`      ]`,
+			`    },`,
+			`    "example.com/schema.Order": {`,
+			`      "type": "object",`,
+			`      "properties": {`,
+			`        "Total": {`,
+			`          "type": "number"`,
+			`        },`,
+			`        "Owner": {`,
+			`          "$ref": "#/$defs/example.com~1schema.User"`,
+			`        }`,
+			`      },`,
+			`      "required": [`,
+			`        "Total"`,
+			`      ]`,
+			`    },`,
+			`    "example.com/schema.User": {`,
+			`      "type": "object",`,
+			`      "properties": {`,
+			`        "name": {`,
+			`          "type": "string",`,
+			`          "description": "display name"`,
+			`        },`,
+			`        "email": {`,
+			`          "type": "string"`,
+			`        },`,
+			`        "age": {`,
+			`          "type": "string"`,
+			`        },`,
+			`        "created": {`,
+			`          "type": "string",`,
+			`          "format": "date-time"`,
+			`        },`,
+			`        "Avatar": {`,
+			`          "type": "string",`,
+			`          "contentEncoding": "base64"`,
+			`        },`,
+			`        "friends": {`,
+			`          "type": "array",`,
+			`          "items": {`,
+			`            "$ref": "#/$defs/example.com~1schema.User"`,
+			`          }`,
+			`        },`,
+			`        "Labels": {`,
+			`          "type": "object",`,
+			`          "additionalProperties": {`,
+			`            "type": "string"`,
+			`          }`,
+			`        },`,
+			`        "address": {`,
+			`          "type": "object",`,
+			`          "properties": {`,
+			`            "city": {`,
+			`              "type": "string"`,
+			`            }`,
+			`          },`,
+			`          "required": [`,
+			`            "city"`,
+			`          ]`,
+			`        },`,
+			`        "id": {`,
+			`          "type": "string"`,
+			`        }`,
+			`      },`,
+			`      "required": [`,
+			`        "name",`,
+			`        "id"`,
+			`      ]`,
+			`    }`,
+			`  }`,
+			`}`,
+		)
+		if stdout != want {
+			t.Errorf("output:\n%s\nwant:\n%s", stdout, want)
+		}
+	})
+
+	t.Run("type", func(t *testing.T) {
+		stdout, code, stderr := runSchema(t, "-type", "example.com/schema.Base", "./...")
+		if code != 0 {
+			t.Fatalf("exit code = %d, want 0:\n%s", code, stderr)
+		}
+
+		var doc struct {
+			Defs map[string]json.RawMessage `json:"$defs"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &doc); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+		}
+		if len(doc.Defs) != 1 || doc.Defs["example.com/schema.Base"] == nil {
+			t.Errorf("unexpected definitions:\n%s", stdout)
+		}
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, code, stderr := runSchema(t, "-type", "Missing", "./...")
+		if code != 1 || !strings.Contains(stderr, `type "Missing": no struct type found`) {
+			t.Errorf("exit code = %d, want 1 with missing type error:\n%s", code, stderr)
+		}
+	})
+}
+
+func runSchema(t *testing.T, args ...string) (stdout string, exitCode int, stderr string) {
+	t.Helper()
+
+	var outBuf, errBuf bytes.Buffer
+	cmd := schemaCmd{
+		Analyzer: requiredfield.New(requiredfield.Options{}),
+		Stdout:   &outBuf,
+		Stderr:   &errBuf,
+	}
+	exitCode = cmd.Run(args)
+	return outBuf.String(), exitCode, errBuf.String()
+}
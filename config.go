@@ -2,25 +2,197 @@ package requiredfield
 
 import (
 	"bufio"
+	"cmp"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"iter"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
+// Config specifies fields that are required
+// in addition to those marked with "// required" comments,
+// and packages where required fields are not enforced.
+//
+// The zero value is an empty configuration.
+// Pass a Config to New to build an analyzer that uses it.
+type Config struct {
+	rc requiredConfig
+}
+
+// Require marks a field as required.
+// The field must be specified in the form "package/path.Type.Field",
+// optionally followed by whitespace-separated options
+// in the form "key=value".
+// This accepts the same values as the -required flag.
+func (c *Config) Require(spec string) error {
+	return c.rc.addRequiredFieldFrom(spec, "Config.Require")
+}
+
+// AddRule marks a group of fields of the same type as required.
+func (c *Config) AddRule(rule Rule) error {
+	return c.rc.applyRule(&rule, "Config.AddRule")
+}
+
+// Exempt disables enforcement of required fields
+// in packages that match any of the given patterns.
+//
+// Patterns are import paths that may end with "/..."
+// to match all packages under a path.
+func (c *Config) Exempt(patterns ...string) error {
+	return c.rc.addExempt(patterns)
+}
+
+// RequireTag marks fields with the given struct tags as required.
+// Tags are specified in the form "key:value",
+// e.g. "validate:required" for fields tagged `validate:"required"`.
+// A field matches if the tag's value is a comma-separated list
+// that includes the given value.
+// This accepts the same values as the -tag flag.
+func (c *Config) RequireTag(specs ...string) error {
+	return c.rc.addTags(specs)
+}
+
+// ImportSchema marks fields as required
+// based on the "required" lists of schemas in a JSON Schema
+// or OpenAPI document in the JSON format.
+//
+// types maps schema names to Go types in the form "package/path.Type".
+// Schema names are looked up under "components/schemas",
+// "definitions", and "$defs",
+// or may be JSON pointers into the document starting with "#".
+// Required properties are matched against Go fields
+// by their names in JSON.
+func (c *Config) ImportSchema(path string, types map[string]string) error {
+	return c.rc.importSchema(&SchemaImport{File: path, Types: types}, nil)
+}
+
+// Apply merges a structured configuration into this configuration.
+// Relative paths in it are resolved relative to
+// the current working directory.
+func (c *Config) Apply(cfg FileConfig) error {
+	return c.rc.apply(&cfg, nil)
+}
+
+// LoadFile loads configuration from the file at the given path.
+// Files with a ".json" extension are parsed as JSON,
+// and all other files as requiredfield.rc files.
+func (c *Config) LoadFile(path string) error {
+	return c.rc.LoadFile(path)
+}
+
+// Parse parses a configuration in the requiredfield.rc format.
+func (c *Config) Parse(r io.Reader) error {
+	return c.rc.Parse(r)
+}
+
+// ParseJSON parses a configuration in the JSON format.
+func (c *Config) ParseJSON(r io.Reader) error {
+	return c.rc.ParseJSON(r)
+}
+
 // requiredConfig holds configuration for fields
 // marked as required via command-line flags.
 type requiredConfig struct {
 	requiredFields map[typeSpec][]string // "package/path.Type" -> []Field
+
+	// fieldRules holds options for each entry in requiredFields.
+	// Entries are in the same order as requiredFields,
+	// and nil entries apply everywhere.
+	fieldRules map[typeSpec][]*fieldRule
+
+	// fieldOrigins records where each entry in requiredFields
+	// was configured, in the same order as requiredFields.
+	// See configOrigin.
+	fieldOrigins map[typeSpec][]string
+
+	// exempt is a list of patterns matching packages
+	// where required fields are not enforced.
+	exempt []packagePattern
+
+	// tags is a list of struct tags that mark fields as required
+	// in addition to "// required" comments.
+	tags []requiredTag
+
+	// loaded is the set of absolute paths of files
+	// that have been loaded into this configuration.
+	// Files included more than once are loaded only once.
+	loaded map[string]struct{}
+}
+
+// clone returns a copy of this configuration
+// that doesn't share any state with it.
+func (c *requiredConfig) clone() requiredConfig {
+	cloneMap := func(m map[typeSpec][]string) map[typeSpec][]string {
+		if m == nil {
+			return nil
+		}
+		out := make(map[typeSpec][]string, len(m))
+		for k, v := range m {
+			out[k] = slices.Clone(v)
+		}
+		return out
+	}
+
+	var rules map[typeSpec][]*fieldRule
+	if c.fieldRules != nil {
+		rules = make(map[typeSpec][]*fieldRule, len(c.fieldRules))
+		for k, v := range c.fieldRules {
+			// fieldRules are never modified after creation,
+			// so it's safe to share them.
+			rules[k] = slices.Clone(v)
+		}
+	}
+
+	return requiredConfig{
+		requiredFields: cloneMap(c.requiredFields),
+		fieldRules:     rules,
+		fieldOrigins:   cloneMap(c.fieldOrigins),
+		exempt:         slices.Clone(c.exempt),
+		tags:           slices.Clone(c.tags),
+		loaded:         maps.Clone(c.loaded),
+	}
+}
+
+// _flagOrigin is the origin of fields marked required with -required.
+const _flagOrigin = "-required"
+
+// configOrigin describes where a configuration entry came from:
+// the innermost file in includeStack and the location inside it.
+// If includeStack is empty, only the location is used.
+func configOrigin(includeStack []string, loc string) string {
+	if len(includeStack) == 0 {
+		return loc
+	}
+	return includeStack[len(includeStack)-1] + ":" + loc
 }
 
 // parseRequiredConfig parses a requiredfield.rc configuration file
 // and returns a requiredConfig.
-// Each line in the file should be in the format: "required pkg.Type.Field"
+// Each line in the file should be in the format: "required pkg.Type.Field",
+// optionally followed by options in the form "key=value".
 // Empty lines and lines starting with "#" are ignored.
+//
+// Files referenced with "include" are resolved relative to
+// the current working directory.
+// Use LoadFile to resolve them relative to the including file.
 func (c *requiredConfig) Parse(r io.Reader) error {
+	return c.parse(r, nil)
+}
+
+// parse parses a requiredfield.rc configuration file.
+//
+// includeStack is the list of absolute paths of files
+// that are currently being loaded, with the innermost file last.
+// It's used to resolve includes and detect cycles.
+func (c *requiredConfig) parse(r io.Reader, includeStack []string) error {
 	scanner := bufio.NewScanner(r)
 	lineNum := 0
 	for scanner.Scan() {
@@ -36,10 +208,35 @@ func (c *requiredConfig) Parse(r io.Reader) error {
 			key, value, _ := strings.Cut(line, " ")
 			switch key {
 			case "required":
-				if err := c.addRequiredField(value); err != nil {
+				origin := configOrigin(includeStack, strconv.Itoa(lineNum))
+				if err := c.addRequiredFieldFrom(value, origin); err != nil {
 					return fmt.Errorf("add required field: %w", err)
 				}
 
+			case "exempt":
+				if err := c.addExempt(strings.Fields(value)); err != nil {
+					return fmt.Errorf("exempt: %w", err)
+				}
+
+			case "tag":
+				if err := c.addTags(strings.Fields(value)); err != nil {
+					return fmt.Errorf("tag: %w", err)
+				}
+
+			case "schema":
+				cfg, err := parseSchemaConfig(value)
+				if err == nil {
+					err = c.importSchema(cfg, includeStack)
+				}
+				if err != nil {
+					return fmt.Errorf("schema: %w", err)
+				}
+
+			case "include":
+				if err := c.include(value, includeStack); err != nil {
+					return fmt.Errorf("include: %w", err)
+				}
+
 			default:
 				return fmt.Errorf("unknown key %q", key)
 			}
@@ -58,44 +255,202 @@ func (c *requiredConfig) Parse(r io.Reader) error {
 func (c *requiredConfig) RegisterFlags(flag *flag.FlagSet) {
 	flag.Func(
 		"required",
-		"mark field as required (e.g. pkg.Type.Field); can be specified multiple times",
+		"mark field as required (e.g. pkg.Type.Field), optionally followed by options (e.g. packages=example.com/...); can be specified multiple times",
 		c.addRequiredField,
 	)
 
 	flag.Func(
-		"config",
-		"load required field specifications from file; suggested only for standalone usage (not via 'go vet')",
-		func(path string) error {
-			f, err := os.Open(path)
-			if err != nil {
-				return err
-			}
-			defer func() { _ = f.Close() }()
+		"exempt",
+		"do not enforce required fields in packages matching this pattern (e.g. example.com/gen/...); can be specified multiple times",
+		func(pattern string) error {
+			return c.addExempt([]string{pattern})
+		},
+	)
 
-			return c.Parse(f)
+	flag.Func(
+		"tag",
+		"mark fields with this struct tag as required, in the form key:value (e.g. validate:required); can be specified multiple times",
+		func(spec string) error {
+			return c.addTags([]string{spec})
 		},
 	)
+
+	flag.Func(
+		"config",
+		"load required field specifications from file (.rc or .json); suggested only for standalone usage (not via 'go vet')",
+		c.LoadFile,
+	)
+}
+
+// LoadFile loads configuration from the file at the given path
+// and merges it into this configuration.
+//
+// Files with a ".json" extension are parsed with ParseJSON.
+// All other files are parsed as requiredfield.rc files.
+func (c *requiredConfig) LoadFile(path string) error {
+	return c.loadFile(path, nil)
 }
 
-// addRequiredField parses and adds a required field specification.
+func (c *requiredConfig) loadFile(path string, includeStack []string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(includeStack, absPath) {
+		cycle := append(slices.Clone(includeStack), absPath)
+		return fmt.Errorf("include cycle: %v", strings.Join(cycle, " -> "))
+	}
+	if _, ok := c.loaded[absPath]; ok {
+		// Already loaded, e.g. included by two different files.
+		return nil
+	}
+	if c.loaded == nil {
+		c.loaded = make(map[string]struct{})
+	}
+	c.loaded[absPath] = struct{}{}
+	includeStack = append(slices.Clip(includeStack), absPath)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = c.parseJSON(f, includeStack)
+	default:
+		err = c.parse(f, includeStack)
+	}
+	if err != nil {
+		return fmt.Errorf("%v:%w", path, err)
+	}
+	return nil
+}
+
+// include loads the file at the given path into this configuration.
+// Relative paths are resolved relative to the directory
+// of the innermost file in includeStack,
+// or the current working directory if the stack is empty.
+func (c *requiredConfig) include(path string, includeStack []string) error {
+	if path == "" {
+		return errors.New("no file specified")
+	}
+
+	return c.loadFile(resolveIncludePath(path, includeStack), includeStack)
+}
+
+// resolveIncludePath resolves a path referenced by a configuration file
+// relative to the directory of the innermost file in includeStack.
+// Paths are returned unchanged if they're absolute
+// or if the stack is empty.
+func resolveIncludePath(path string, includeStack []string) string {
+	if !filepath.IsAbs(path) && len(includeStack) > 0 {
+		path = filepath.Join(filepath.Dir(includeStack[len(includeStack)-1]), path)
+	}
+	return path
+}
+
+// addRequiredField parses and adds a required field specification
+// passed to the -required flag.
+// See addRequiredFieldFrom for the format.
+func (c *requiredConfig) addRequiredField(value string) error {
+	return c.addRequiredFieldFrom(value, _flagOrigin)
+}
+
+// addRequiredFieldFrom parses and adds a required field specification
+// that was configured at the given origin.
 // The spec must be in the format: package/path.TypeName.FieldName
-func (c *requiredConfig) addRequiredField(spec string) error {
-	if c.requiredFields == nil {
-		c.requiredFields = make(map[typeSpec][]string)
+// optionally followed by whitespace-separated options
+// in the form "key=value" (see parseFieldRule).
+//
+// Fields of anonymous structs nested inside a named type
+// may be specified by listing the fields leading to them,
+// e.g. package/path.TypeName.Outer.Inner.FieldName.
+func (c *requiredConfig) addRequiredFieldFrom(value, origin string) error {
+	spec, opts := cutFieldSpec(strings.TrimSpace(value))
+
+	rule, err := parseFieldRule(strings.Fields(opts))
+	if err != nil {
+		return err
 	}
 
+	return c.addRequired(spec, rule, origin)
+}
+
+// cutFieldSpec splits the field specification at the start of value
+// from the text following it.
+// The specification ends at the first whitespace
+// that is not inside a list of type arguments.
+func cutFieldSpec(value string) (spec, rest string) {
+	depth := 0
+	for i, r := range value {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case depth <= 0 && unicode.IsSpace(r):
+			return value[:i], value[i:]
+		}
+	}
+	return value, ""
+}
+
+// addRequired adds a required field specification
+// in the format package/path.TypeName.FieldName
+// with the given options.
+// origin describes where the field was configured (see configOrigin).
+func (c *requiredConfig) addRequired(spec string, rule *fieldRule, origin string) error {
 	typeSpec, fieldName, err := parseFieldSpec(spec)
 	if err != nil {
 		return fmt.Errorf(`expected "package/path.Type.Field": %w`, err)
 	}
 
-	c.requiredFields[typeSpec] = append(c.requiredFields[typeSpec], fieldName)
+	c.addRequiredTo(typeSpec, fieldName, rule, origin)
 	return nil
 }
 
+// addRequiredTo marks a field of the given type as required.
+// name is the name of the field in Go,
+// or its name in JSON if the rule says so (see fieldRule.JSONName).
+func (c *requiredConfig) addRequiredTo(ts typeSpec, name string, rule *fieldRule, origin string) {
+	if c.requiredFields == nil {
+		c.requiredFields = make(map[typeSpec][]string)
+		c.fieldRules = make(map[typeSpec][]*fieldRule)
+		c.fieldOrigins = make(map[typeSpec][]string)
+	}
+
+	c.requiredFields[ts] = append(c.requiredFields[ts], name)
+	c.fieldRules[ts] = append(c.fieldRules[ts], rule)
+	c.fieldOrigins[ts] = append(c.fieldOrigins[ts], origin)
+}
+
+// addExempt adds patterns for packages
+// where required fields are not enforced.
+func (c *requiredConfig) addExempt(patterns []string) error {
+	exempt, err := appendPackagePatterns(c.exempt, patterns)
+	if err != nil {
+		return err
+	}
+	c.exempt = exempt
+	return nil
+}
+
+// IsExempt reports whether required fields are not enforced
+// in the package with the given import path.
+func (c *requiredConfig) IsExempt(pkgPath string) bool {
+	if c == nil || len(c.exempt) == 0 {
+		return false
+	}
+	return !(&fieldRule{Exclude: c.exempt}).AppliesTo(pkgPath)
+}
+
 // RequiredFields returns the list of required field names
 // for the given package path and type name.
 // Returns nil if no fields are configured for this type.
+//
+// This includes fields that are limited to certain packages.
 func (c *requiredConfig) RequiredFields(pkgPath, typeName string) []string {
 	if c == nil {
 		return nil
@@ -106,13 +461,177 @@ func (c *requiredConfig) RequiredFields(pkgPath, typeName string) []string {
 	}]
 }
 
+// requiredFieldsOf returns the list of required field names
+// for the given type that apply to code
+// in the package with the import path scope.
+// Returns nil if no fields are configured for this type.
+func (c *requiredConfig) requiredFieldsOf(ts typeSpec, scope string) []string {
+	var result []string
+	for name := range c.fieldsOf(ts, scope) {
+		result = append(result, name)
+	}
+	return result
+}
+
+// fieldsOf iterates over required fields of the given type
+// that apply to code in the package with the import path scope,
+// along with the options for each.
+//
+// The same field may be yielded multiple times
+// if it was configured more than once.
+func (c *requiredConfig) fieldsOf(ts typeSpec, scope string) iter.Seq2[string, *fieldRule] {
+	return func(yield func(string, *fieldRule) bool) {
+		if c == nil {
+			return
+		}
+
+		rules := c.fieldRules[ts]
+		for i, name := range c.requiredFields[ts] {
+			var rule *fieldRule
+			if i < len(rules) {
+				rule = rules[i]
+			}
+			if !rule.AppliesTo(scope) {
+				continue
+			}
+			if !yield(name, rule) {
+				return
+			}
+		}
+	}
+}
+
+// configuredField is a field configured as required
+// and where it was configured.
+type configuredField struct {
+	Type   typeSpec
+	Name   string
+	Rule   *fieldRule
+	Origin string // see configOrigin
+}
+
+// allConfiguredFields returns the fields configured for all types,
+// ordered by type and then in the order they were configured.
+func (c *requiredConfig) allConfiguredFields() []configuredField {
+	if c == nil {
+		return nil
+	}
+
+	specs := make([]typeSpec, 0, len(c.requiredFields))
+	for ts := range c.requiredFields {
+		if !slices.ContainsFunc(specs, func(other typeSpec) bool {
+			return other.packagePath == ts.packagePath && other.typeName == ts.typeName
+		}) {
+			specs = append(specs, ts)
+		}
+	}
+	slices.SortFunc(specs, func(a, b typeSpec) int {
+		return cmp.Or(
+			strings.Compare(a.packagePath, b.packagePath),
+			strings.Compare(a.typeName, b.typeName),
+		)
+	})
+
+	var fields []configuredField
+	for _, ts := range specs {
+		fields = append(fields, c.configuredFields(ts.packagePath, ts.typeName)...)
+	}
+	return fields
+}
+
+// configuredFields returns all fields configured for the named type
+// with the given package path and type name,
+// including those configured for specific instantiations of the type,
+// regardless of the packages they apply to.
+//
+// Fields are ordered by type and then in the order they were configured.
+func (c *requiredConfig) configuredFields(pkgPath, typeName string) []configuredField {
+	if c == nil {
+		return nil
+	}
+
+	var specs []typeSpec
+	for ts := range c.requiredFields {
+		if ts.packagePath == pkgPath && ts.typeName == typeName {
+			specs = append(specs, ts)
+		}
+	}
+	slices.SortFunc(specs, func(a, b typeSpec) int {
+		return strings.Compare(a.typeArgs, b.typeArgs)
+	})
+
+	var fields []configuredField
+	for _, ts := range specs {
+		origins := c.fieldOrigins[ts]
+		rules := c.fieldRules[ts]
+		for i, name := range c.requiredFields[ts] {
+			var (
+				origin string
+				rule   *fieldRule
+			)
+			if i < len(origins) {
+				origin = origins[i]
+			}
+			if i < len(rules) {
+				rule = rules[i]
+			}
+			fields = append(fields, configuredField{
+				Type:   ts,
+				Name:   name,
+				Rule:   rule,
+				Origin: origin,
+			})
+		}
+	}
+	return fields
+}
+
+// typeSpec identifies a struct type that may have configured fields.
+//
+// For named types, this is the package path and the type name,
+// and for instantiations of generic types,
+// optionally the type arguments.
+// For anonymous struct types nested inside a named type,
+// see typeSpec.nested.
 type typeSpec struct {
 	packagePath string
 	typeName    string
+
+	// typeArgs is a comma-separated list of type arguments
+	// with all whitespace removed, as returned by normalizeTypeArgs.
+	// If empty, the spec applies to all instantiations of the type.
+	typeArgs string
+}
+
+// nested returns a typeSpec for an anonymous struct type
+// held in the given field of this type.
+//
+// This is compatible with how parseFieldSpec parses
+// "pkg.Outer.Inner.Field":
+// the result for "pkg.Outer" nested in "Inner"
+// has package path "pkg.Outer" and type name "Inner".
+func (ts typeSpec) nested(field string) typeSpec {
+	return typeSpec{
+		packagePath: ts.String(),
+		typeName:    field,
+	}
+}
+
+// String returns the spec in the same format
+// that parseFieldSpec accepts, without the field name.
+func (ts typeSpec) String() string {
+	s := ts.packagePath + "." + ts.typeName
+	if ts.typeArgs != "" {
+		s += "[" + ts.typeArgs + "]"
+	}
+	return s
 }
 
 func parseFieldSpec(spec string) (typeSpec, string, error) {
-	idx := strings.LastIndex(spec, ".")
+	idx, err := lastDot(spec)
+	if err != nil {
+		return typeSpec{}, "", err
+	}
 	if idx == -1 {
 		return typeSpec{}, "", errors.New("no field or type specified")
 	}
@@ -121,22 +640,86 @@ func parseFieldSpec(spec string) (typeSpec, string, error) {
 	if fieldName == "" {
 		return typeSpec{}, "", errors.New("field name is empty")
 	}
+	if strings.ContainsAny(fieldName, "[]") {
+		return typeSpec{}, "", fmt.Errorf("unexpected type arguments in field name %q", fieldName)
+	}
+
+	ts, err := parseTypeSpec(spec)
+	if err != nil {
+		return typeSpec{}, "", err
+	}
+	return ts, fieldName, nil
+}
 
-	idx = strings.LastIndex(spec, ".")
+// parseTypeSpec parses a type specification
+// in the form "package/path.Type" or "package/path.Type[Args]".
+func parseTypeSpec(spec string) (typeSpec, error) {
+	idx, err := lastDot(spec)
+	if err != nil {
+		return typeSpec{}, err
+	}
 	if idx == -1 {
-		return typeSpec{}, "", errors.New("no package or type specified")
+		return typeSpec{}, errors.New("no package or type specified")
 	}
 
 	packagePath, typeName := spec[:idx], spec[idx+1:]
 	if packagePath == "" {
-		return typeSpec{}, "", errors.New("package path is empty")
+		return typeSpec{}, errors.New("package path is empty")
+	}
+
+	var typeArgs string
+	if start := strings.IndexByte(typeName, '['); start >= 0 {
+		if !strings.HasSuffix(typeName, "]") {
+			return typeSpec{}, fmt.Errorf("unexpected text after type arguments in %q", typeName)
+		}
+
+		typeName, typeArgs = typeName[:start], normalizeTypeArgs(typeName[start+1:len(typeName)-1])
+		if typeArgs == "" {
+			return typeSpec{}, errors.New("type arguments are empty")
+		}
 	}
 	if typeName == "" {
-		return typeSpec{}, "", errors.New("type name is empty")
+		return typeSpec{}, errors.New("type name is empty")
 	}
 
 	return typeSpec{
 		packagePath: packagePath,
 		typeName:    typeName,
-	}, fieldName, nil
+		typeArgs:    typeArgs,
+	}, nil
+}
+
+// lastDot returns the index of the last "." in spec
+// that is not inside a list of type arguments,
+// or -1 if there isn't one.
+func lastDot(spec string) (int, error) {
+	depth := 0
+	for i := len(spec) - 1; i >= 0; i-- {
+		switch spec[i] {
+		case ']':
+			depth++
+		case '[':
+			depth--
+			if depth < 0 {
+				return -1, errors.New("unmatched '['")
+			}
+		case '.':
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+
+	if depth != 0 {
+		return -1, errors.New("unmatched ']'")
+	}
+	return -1, nil
+}
+
+// normalizeTypeArgs normalizes a list of type arguments
+// so that it may be compared with other lists as a string.
+// Type arguments must be written with fully qualified package paths,
+// e.g. "*net/http.Request, int".
+func normalizeTypeArgs(args string) string {
+	return strings.Join(strings.Fields(args), "")
 }
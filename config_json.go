@@ -0,0 +1,227 @@
+package requiredfield
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// FileConfig is the structured form of a requiredfield configuration.
+//
+// It may be loaded from a JSON file with the -config flag,
+// or embedded inside another tool's configuration
+// (e.g. the settings block of a golangci-lint plugin)
+// by decoding into it and passing it to Config.Apply.
+//
+// Keys are lowercase and kebab-cased
+// so that the same shape may be written in YAML.
+type FileConfig struct {
+	// Include is a list of other configuration files to load
+	// before this one.
+	// Relative paths are resolved relative to the including file.
+	//
+	// This is equivalent to "include" lines in an .rc file.
+	Include []string `json:"include,omitempty"`
+
+	// Required is a list of field specifications
+	// in the form "package/path.Type.Field",
+	// optionally followed by options in the form "key=value".
+	//
+	// This is equivalent to "required" lines in an .rc file.
+	Required []string `json:"required,omitempty"`
+
+	// Exempt is a list of package patterns
+	// where required fields are not enforced at all.
+	//
+	// This is equivalent to "exempt" lines in an .rc file.
+	Exempt []string `json:"exempt,omitempty"`
+
+	// Tags is a list of struct tags in the form "key:value"
+	// that mark fields as required.
+	//
+	// This is equivalent to "tag" lines in an .rc file.
+	Tags []string `json:"tags,omitempty"`
+
+	// Rules groups fields of a single type together.
+	Rules []Rule `json:"rules,omitempty"`
+
+	// Schemas imports required fields from JSON Schema
+	// or OpenAPI documents.
+	//
+	// This is equivalent to "schema" lines in an .rc file.
+	Schemas []SchemaImport `json:"schemas,omitempty"`
+}
+
+// Rule is a group of required fields of the same type
+// and options that control where and how they're enforced.
+//
+// Rules may be added to a Config with Config.AddRule,
+// or listed under "rules" in a JSON configuration file.
+type Rule struct {
+	// Type is the type that owns the fields
+	// in the form "package/path.Type".
+	Type string `json:"type"` // required
+
+	// Fields is a list of field names in Type that are required.
+	Fields []string `json:"fields"` // required
+
+	// Packages is a list of package patterns
+	// where the fields are required.
+	// If empty, the fields are required in all packages.
+	Packages []string `json:"packages,omitempty"`
+
+	// Exclude is a list of package patterns
+	// where the fields are not required.
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Severity of diagnostics for these fields:
+	// "error" (default), "warning", or "info".
+	Severity string `json:"severity,omitempty"`
+
+	// Category of diagnostics for these fields.
+	// Defaults to "missing-required".
+	Category string `json:"category,omitempty"`
+}
+
+// ParseJSON parses a JSON configuration
+// in the format described by FileConfig,
+// and merges it into this configuration.
+//
+// Files referenced with "include" are resolved relative to
+// the current working directory.
+// Use LoadFile to resolve them relative to the including file.
+func (c *requiredConfig) ParseJSON(r io.Reader) error {
+	return c.parseJSON(r, nil)
+}
+
+// parseJSON parses a JSON configuration.
+// includeStack is the same as for parse.
+func (c *requiredConfig) parseJSON(r io.Reader, includeStack []string) error {
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.DisallowUnknownFields()
+
+	var cfg FileConfig
+	if err := dec.Decode(&cfg); err != nil {
+		var synErr *json.SyntaxError
+		if errors.As(err, &synErr) {
+			return fmt.Errorf("%d:%w", lineAt(bs, synErr.Offset), err)
+		}
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return fmt.Errorf("%d:%w", lineAt(bs, typeErr.Offset), err)
+		}
+		return err
+	}
+
+	return c.apply(&cfg, includeStack)
+}
+
+// apply merges a structured configuration into this configuration.
+func (c *requiredConfig) apply(cfg *FileConfig, includeStack []string) error {
+	for i, path := range cfg.Include {
+		if err := c.include(path, includeStack); err != nil {
+			return fmt.Errorf("include[%d]: %w", i, err)
+		}
+	}
+
+	for i, spec := range cfg.Required {
+		origin := configOrigin(includeStack, fmt.Sprintf("required[%d]", i))
+		if err := c.addRequiredFieldFrom(spec, origin); err != nil {
+			return fmt.Errorf("required[%d]: add required field: %w", i, err)
+		}
+	}
+
+	if err := c.addExempt(cfg.Exempt); err != nil {
+		return fmt.Errorf("exempt: %w", err)
+	}
+
+	if err := c.addTags(cfg.Tags); err != nil {
+		return fmt.Errorf("tags: %w", err)
+	}
+
+	for i, rule := range cfg.Rules {
+		origin := configOrigin(includeStack, fmt.Sprintf("rules[%d]", i))
+		if err := c.applyRule(&rule, origin); err != nil {
+			return fmt.Errorf("rules[%d]: %w", i, err)
+		}
+	}
+
+	for i, schema := range cfg.Schemas {
+		if err := c.importSchema(&schema, includeStack); err != nil {
+			return fmt.Errorf("schemas[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *requiredConfig) applyRule(rule *Rule, origin string) error {
+	if rule.Type == "" {
+		return errors.New("type is empty")
+	}
+	if len(rule.Fields) == 0 {
+		return errors.New("no fields specified")
+	}
+
+	fr, err := rule.fieldRule()
+	if err != nil {
+		return err
+	}
+
+	for i, field := range rule.Fields {
+		if err := c.addRequired(rule.Type+"."+field, fr, origin); err != nil {
+			return fmt.Errorf("fields[%d]: add required field: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// fieldRule builds the options for fields in this rule.
+// It returns nil if the rule has no options.
+func (rule *Rule) fieldRule() (*fieldRule, error) {
+	var (
+		fr  fieldRule
+		err error
+	)
+	if len(rule.Packages) == 0 && len(rule.Exclude) == 0 && rule.Severity == "" && rule.Category == "" {
+		return nil, nil
+	}
+
+	fr.Packages, err = appendPackagePatterns(nil, rule.Packages)
+	if err != nil {
+		return nil, fmt.Errorf("packages: %w", err)
+	}
+	fr.Exclude, err = appendPackagePatterns(nil, rule.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("exclude: %w", err)
+	}
+	if rule.Severity != "" {
+		fr.Severity, err = parseSeverity(rule.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("severity: %w", err)
+		}
+	}
+	if rule.Category != "" {
+		fr.Category, err = parseCategory(rule.Category)
+		if err != nil {
+			return nil, fmt.Errorf("category: %w", err)
+		}
+	}
+
+	return &fr, nil
+}
+
+// lineAt reports the 1-indexed line number
+// of the given byte offset in bs.
+func lineAt(bs []byte, offset int64) int {
+	offset = min(max(offset, 0), int64(len(bs)))
+	return bytes.Count(bs[:offset], []byte("\n")) + 1
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -45,6 +46,50 @@ func TestParseFieldSpec(t *testing.T) {
 			},
 			wantField: "Field",
 		},
+		{
+			name:         "nested anonymous struct",
+			spec:         "example.com/pkg.Outer.Inner.Field",
+			wantTypeSpec: typeSpec{packagePath: "example.com/pkg", typeName: "Outer"}.nested("Inner"),
+			wantField:    "Field",
+		},
+		{
+			name: "deeply nested anonymous struct",
+			spec: "example.com/pkg.Outer.A.B.Field",
+			wantTypeSpec: typeSpec{packagePath: "example.com/pkg", typeName: "Outer"}.
+				nested("A").
+				nested("B"),
+			wantField: "Field",
+		},
+		{
+			name: "type arguments",
+			spec: "example.com/pkg.Container[int].Value",
+			wantTypeSpec: typeSpec{
+				packagePath: "example.com/pkg",
+				typeName:    "Container",
+				typeArgs:    "int",
+			},
+			wantField: "Value",
+		},
+		{
+			name: "qualified type arguments",
+			spec: "example.com/pkg.Pair[*net/http.Request, map[string]example.com/pkg.Value].Key",
+			wantTypeSpec: typeSpec{
+				packagePath: "example.com/pkg",
+				typeName:    "Pair",
+				typeArgs:    "*net/http.Request,map[string]example.com/pkg.Value",
+			},
+			wantField: "Key",
+		},
+		{
+			name: "nested in instantiation",
+			spec: "example.com/pkg.Outer[int].Inner.Field",
+			wantTypeSpec: typeSpec{
+				packagePath: "example.com/pkg",
+				typeName:    "Outer",
+				typeArgs:    "int",
+			}.nested("Inner"),
+			wantField: "Field",
+		},
 	}
 
 	for _, tt := range tests {
@@ -91,6 +136,36 @@ func TestParseFieldSpec_Errors(t *testing.T) {
 			spec:    "pkg.Type",
 			wantErr: []string{"no package or type specified"},
 		},
+		{
+			name:    "empty type arguments",
+			spec:    "pkg.Type[].Field",
+			wantErr: []string{"type arguments are empty"},
+		},
+		{
+			name:    "missing type name",
+			spec:    "pkg.[int].Field",
+			wantErr: []string{"type name is empty"},
+		},
+		{
+			name:    "unmatched open bracket",
+			spec:    "pkg.Type[int.Field",
+			wantErr: []string{"unmatched '['"},
+		},
+		{
+			name:    "unmatched close bracket",
+			spec:    "pkg.Typeint].Field",
+			wantErr: []string{"unmatched ']'"},
+		},
+		{
+			name:    "text after type arguments",
+			spec:    "pkg.Type[int]x.Field",
+			wantErr: []string{"unexpected text after type arguments"},
+		},
+		{
+			name:    "type arguments on field",
+			spec:    "pkg.Type.Field[int]",
+			wantErr: []string{"unexpected type arguments in field name"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -535,3 +610,504 @@ func TestRequiredConfig_configFlagMergeWithRequired(t *testing.T) {
 func joinLines(lines ...string) string {
 	return strings.Join(lines, "\n") + "\n"
 }
+
+func TestRequiredConfig_ParseJSON_matchesRC(t *testing.T) {
+	tests := []struct {
+		name     string
+		giveRC   string
+		giveJSON string
+	}{
+		{
+			name:     "empty",
+			giveJSON: `{}`,
+		},
+		{
+			name:     "single field",
+			giveRC:   joinLines("required pkg.User.ID"),
+			giveJSON: `{"required": ["pkg.User.ID"]}`,
+		},
+		{
+			name: "multiple types",
+			giveRC: joinLines(
+				"required github.com/pkg.User.ID",
+				"required github.com/other.Config.Key",
+			),
+			giveJSON: `{
+				"required": [
+					"github.com/pkg.User.ID",
+					"github.com/other.Config.Key"
+				]
+			}`,
+		},
+		{
+			name: "rule groups fields",
+			giveRC: joinLines(
+				"required net/http.Request.Method",
+				"required net/http.Request.URL",
+			),
+			giveJSON: `{
+				"rules": [
+					{"type": "net/http.Request", "fields": ["Method", "URL"]}
+				]
+			}`,
+		},
+		{
+			name: "required and rules",
+			giveRC: joinLines(
+				"required pkg.User.ID",
+				"required pkg.User.Name",
+				"required pkg.User.Email",
+				"required pkg.Config.Key",
+			),
+			giveJSON: `{
+				"required": ["pkg.User.ID"],
+				"rules": [
+					{"type": "pkg.User", "fields": ["Name", "Email"]},
+					{"type": "pkg.Config", "fields": ["Key"]}
+				]
+			}`,
+		},
+		{
+			name: "tags",
+			giveRC: joinLines(
+				"tag validate:required",
+				"tag binding:required",
+			),
+			giveJSON: `{"tags": ["validate:required", "binding:required"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := new(requiredConfig)
+			if err := rc.Parse(strings.NewReader(tt.giveRC)); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			js := new(requiredConfig)
+			if err := js.ParseJSON(strings.NewReader(tt.giveJSON)); err != nil {
+				t.Fatalf("ParseJSON() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(rc.requiredFields, js.requiredFields) {
+				t.Errorf("requiredFields = %v, want %v", js.requiredFields, rc.requiredFields)
+			}
+			if !reflect.DeepEqual(rc.tags, js.tags) {
+				t.Errorf("tags = %v, want %v", js.tags, rc.tags)
+			}
+		})
+	}
+}
+
+func TestRequiredConfig_ParseJSON_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		give    string
+		wantErr []string
+	}{
+		{
+			name:    "Syntax",
+			give:    "{\n\"required\": [\n}",
+			wantErr: []string{"3:", "invalid character"},
+		},
+		{
+			name:    "UnknownKey",
+			give:    `{"optional": ["pkg.User.ID"]}`,
+			wantErr: []string{`unknown field "optional"`},
+		},
+		{
+			name:    "WrongType",
+			give:    "{\n\"required\": \"pkg.User.ID\"}",
+			wantErr: []string{"2:", "cannot unmarshal"},
+		},
+		{
+			name:    "BadRequired",
+			give:    `{"required": ["pkg.User.ID", "invalid"]}`,
+			wantErr: []string{"required[1]:", "no field or type specified"},
+		},
+		{
+			name:    "Rule/NoType",
+			give:    `{"rules": [{"fields": ["ID"]}]}`,
+			wantErr: []string{"rules[0]:", "type is empty"},
+		},
+		{
+			name:    "Rule/NoFields",
+			give:    `{"rules": [{"type": "pkg.User"}]}`,
+			wantErr: []string{"rules[0]:", "no fields specified"},
+		},
+		{
+			name:    "Rule/BadSeverity",
+			give:    `{"rules": [{"type": "pkg.User", "fields": ["ID"], "severity": "fatal"}]}`,
+			wantErr: []string{"rules[0]: severity:", `unknown severity "fatal"`},
+		},
+		{
+			name:    "Rule/BadPackages",
+			give:    `{"rules": [{"type": "pkg.User", "fields": ["ID"], "packages": [""]}]}`,
+			wantErr: []string{"rules[0]: packages:", "empty package pattern"},
+		},
+		{
+			name:    "Rule/BadField",
+			give:    `{"rules": [{"type": "pkg.User", "fields": ["ID", ""]}]}`,
+			wantErr: []string{"rules[0]: fields[1]:", "field name is empty"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := new(requiredConfig).ParseJSON(strings.NewReader(tt.give))
+			if err == nil {
+				t.Fatalf("ParseJSON() error = nil, want error")
+			}
+
+			errMsg := err.Error()
+			for _, substring := range tt.wantErr {
+				if !strings.Contains(errMsg, substring) {
+					t.Errorf("ParseJSON() error = %q, want to contain %q", errMsg, substring)
+				}
+			}
+		})
+	}
+}
+
+func TestRequiredConfig_ConfigFlag_json(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "requiredfield.json")
+	content := `{
+		"required": ["pkg.User.ID"],
+		"rules": [{"type": "pkg.Config", "fields": ["Key"]}]
+	}`
+	if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+
+	c := new(requiredConfig)
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	c.RegisterFlags(fset)
+
+	if err := fset.Parse([]string{"-config", configPath}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := map[typeSpec][]string{
+		{packagePath: "pkg", typeName: "User"}:   {"ID"},
+		{packagePath: "pkg", typeName: "Config"}: {"Key"},
+	}
+	if !reflect.DeepEqual(c.requiredFields, want) {
+		t.Errorf("requiredFields = %v, want %v", c.requiredFields, want)
+	}
+}
+
+func TestRequiredConfig_LoadFile_errorHasPath(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "bad.rc")
+	if err := os.WriteFile(configPath, []byte(joinLines("", "optional pkg.User.ID")), 0o600); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+
+	err := new(requiredConfig).LoadFile(configPath)
+	if err == nil {
+		t.Fatalf("LoadFile() error = nil, want error")
+	}
+
+	want := configPath + `:2:unknown key "optional"`
+	if got := err.Error(); got != want {
+		t.Errorf("LoadFile() error = %q, want %q", got, want)
+	}
+}
+
+func TestRequiredConfig_LoadFile_include(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"requiredfield.rc": joinLines(
+			"include shared/base.rc",
+			"required pkg.User.Name",
+		),
+		"shared/base.rc": joinLines(
+			"# Relative to shared/.",
+			"include third_party.json",
+			"required pkg.User.ID",
+		),
+		"shared/third_party.json": `{
+			"include": ["net.rc"],
+			"required": ["net/http.Request.Method"]
+		}`,
+		"shared/net.rc": joinLines("required net/http.Request.URL"),
+	})
+
+	c := new(requiredConfig)
+	if err := c.LoadFile(filepath.Join(dir, "requiredfield.rc")); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	want := map[typeSpec][]string{
+		{packagePath: "pkg", typeName: "User"}:         {"ID", "Name"},
+		{packagePath: "net/http", typeName: "Request"}: {"URL", "Method"},
+	}
+	if !reflect.DeepEqual(c.requiredFields, want) {
+		t.Errorf("requiredFields = %v, want %v", c.requiredFields, want)
+	}
+}
+
+func TestRequiredConfig_LoadFile_includeTwice(t *testing.T) {
+	// Including the same file from two places is not a cycle.
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"requiredfield.rc": joinLines("include a.rc", "include b.rc"),
+		"a.rc":             joinLines("include base.rc"),
+		"b.rc":             joinLines("include base.rc"),
+		"base.rc":          joinLines("required pkg.User.ID"),
+	})
+
+	c := new(requiredConfig)
+	if err := c.LoadFile(filepath.Join(dir, "requiredfield.rc")); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	// base.rc is loaded only once.
+	if got := c.RequiredFields("pkg", "User"); !slices.Equal(got, []string{"ID"}) {
+		t.Errorf("RequiredFields() = %v, want [ID]", got)
+	}
+}
+
+func TestRequiredConfig_LoadFile_includeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+
+		// Error message with "$DIR" replaced by the temporary directory.
+		wantErr string
+	}{
+		{
+			name: "NoFile",
+			files: map[string]string{
+				"requiredfield.rc": joinLines("include"),
+			},
+			wantErr: "$DIR/requiredfield.rc:1:include: no file specified",
+		},
+		{
+			name: "MissingFile",
+			files: map[string]string{
+				"requiredfield.rc": joinLines("", "include missing.rc"),
+			},
+			wantErr: "$DIR/requiredfield.rc:2:include: open $DIR/missing.rc: no such file or directory",
+		},
+		{
+			name: "ErrorStack",
+			files: map[string]string{
+				"requiredfield.rc": joinLines("include a/a.rc"),
+				"a/a.rc":           joinLines("# comment", "include b.json"),
+				"a/b.json":         "{\n\"required\": [\"invalid\"]}",
+			},
+			wantErr: "$DIR/requiredfield.rc:1:include: " +
+				"$DIR/a/a.rc:2:include: " +
+				"$DIR/a/b.json:required[0]: add required field: " +
+				`expected "package/path.Type.Field": no field or type specified`,
+		},
+		{
+			name: "SelfCycle",
+			files: map[string]string{
+				"requiredfield.rc": joinLines("include requiredfield.rc"),
+			},
+			wantErr: "$DIR/requiredfield.rc:1:include: include cycle: " +
+				"$DIR/requiredfield.rc -> $DIR/requiredfield.rc",
+		},
+		{
+			name: "Cycle",
+			files: map[string]string{
+				"requiredfield.rc": joinLines("include a.rc"),
+				"a.rc":             joinLines("include b.json"),
+				"b.json":           `{"include": ["./a.rc"]}`,
+			},
+			wantErr: "$DIR/requiredfield.rc:1:include: " +
+				"$DIR/a.rc:1:include: " +
+				"$DIR/b.json:include[0]: include cycle: " +
+				"$DIR/requiredfield.rc -> $DIR/a.rc -> $DIR/b.json -> $DIR/a.rc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+
+			err := new(requiredConfig).LoadFile(filepath.Join(dir, "requiredfield.rc"))
+			if err == nil {
+				t.Fatalf("LoadFile() error = nil, want error")
+			}
+
+			want := strings.ReplaceAll(tt.wantErr, "$DIR", dir)
+			if got := err.Error(); got != want {
+				t.Errorf("LoadFile() error:\ngot:  %v\nwant: %v", got, want)
+			}
+		})
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %v: %v", name, err)
+		}
+	}
+}
+
+func TestRequiredConfig_requiredFieldsOf_scoped(t *testing.T) {
+	rc := joinLines(
+		"required pkg.User.ID",
+		"required pkg.User.Name packages=example.com/prod/...",
+		"required pkg.User.Email exclude=example.com/tools/...",
+		"required pkg.Pair[string, int].Key   packages=example.com/prod",
+	)
+	jsonConfig := `{
+		"required": ["pkg.User.ID"],
+		"rules": [
+			{"type": "pkg.User", "fields": ["Name"], "packages": ["example.com/prod/..."]},
+			{"type": "pkg.User", "fields": ["Email"], "exclude": ["example.com/tools/..."]},
+			{"type": "pkg.Pair[string, int]", "fields": ["Key"], "packages": ["example.com/prod"]}
+		]
+	}`
+
+	user := typeSpec{packagePath: "pkg", typeName: "User"}
+	pair := typeSpec{packagePath: "pkg", typeName: "Pair", typeArgs: "string,int"}
+	tests := []struct {
+		scope string
+		ts    typeSpec
+		want  []string
+	}{
+		{"example.com/prod", user, []string{"ID", "Name", "Email"}},
+		{"example.com/prod/api", user, []string{"ID", "Name", "Email"}},
+		{"example.com/tools/migrate", user, []string{"ID"}},
+		{"example.com/lib", user, []string{"ID", "Email"}},
+		{"example.com/prod", pair, []string{"Key"}},
+		{"example.com/prod/api", pair, nil},
+	}
+
+	for _, format := range []string{"rc", "json"} {
+		t.Run(format, func(t *testing.T) {
+			c := new(requiredConfig)
+			var err error
+			if format == "rc" {
+				err = c.Parse(strings.NewReader(rc))
+			} else {
+				err = c.ParseJSON(strings.NewReader(jsonConfig))
+			}
+			if err != nil {
+				t.Fatalf("parse error = %v", err)
+			}
+
+			for _, tt := range tests {
+				got := c.requiredFieldsOf(tt.ts, tt.scope)
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("requiredFieldsOf(%v, %q) = %v, want %v", tt.ts, tt.scope, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRequiredConfig_IsExempt(t *testing.T) {
+	c := new(requiredConfig)
+	if c.IsExempt("example.com/foo") {
+		t.Errorf("empty config should not exempt any packages")
+	}
+
+	if err := c.Parse(strings.NewReader(joinLines(
+		"exempt example.com/gen/... example.com/legacy",
+	))); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		pkgPath string
+		want    bool
+	}{
+		{"example.com/gen", true},
+		{"example.com/gen/api", true},
+		{"example.com/legacy", true},
+		{"example.com/legacy_test", true},
+		{"example.com/legacy/sub", false},
+		{"example.com/foo", false},
+	}
+	for _, tt := range tests {
+		if got := c.IsExempt(tt.pkgPath); got != tt.want {
+			t.Errorf("IsExempt(%q) = %v, want %v", tt.pkgPath, got, tt.want)
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	var c Config
+	err := errors.Join(
+		c.Require("example.com/foo.User.ID"),
+		c.Require("example.com/foo.User.Name severity=warning"),
+		c.AddRule(Rule{
+			Type:    "example.com/foo.Config",
+			Fields:  []string{"Key", "Secret"},
+			Exclude: []string{"example.com/foo/internal/..."},
+		}),
+		c.Exempt("example.com/foo/gen/..."),
+		c.RequireTag("validate:required", "binding:required"),
+	)
+	if err != nil {
+		t.Fatalf("failed to configure: %v", err)
+	}
+
+	if got, want := c.rc.RequiredFields("example.com/foo", "User"), []string{"ID", "Name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("User fields = %v, want %v", got, want)
+	}
+
+	ts := typeSpec{packagePath: "example.com/foo", typeName: "Config"}
+	if got, want := c.rc.requiredFieldsOf(ts, "example.com/foo"), []string{"Key", "Secret"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Config fields = %v, want %v", got, want)
+	}
+	if got := c.rc.requiredFieldsOf(ts, "example.com/foo/internal/bar"); len(got) != 0 {
+		t.Errorf("Config fields in excluded package = %v, want none", got)
+	}
+
+	if !c.rc.IsExempt("example.com/foo/gen/api") {
+		t.Errorf("expected example.com/foo/gen/api to be exempt")
+	}
+
+	if got, want := len(c.rc.tags), 2; got != want {
+		t.Errorf("got %d tags, want %d", got, want)
+	}
+}
+
+func TestConfig_errors(t *testing.T) {
+	var c Config
+	if err := c.Require("User.ID"); err == nil || !strings.Contains(err.Error(), "no package or type specified") {
+		t.Errorf("Require() error = %v, want spec error", err)
+	}
+	if err := c.AddRule(Rule{Type: "example.com/foo.User"}); err == nil || !strings.Contains(err.Error(), "no fields specified") {
+		t.Errorf("AddRule() error = %v, want fields error", err)
+	}
+	if err := c.Exempt(""); err == nil || !strings.Contains(err.Error(), "empty package pattern") {
+		t.Errorf("Exempt() error = %v, want pattern error", err)
+	}
+}
+
+func TestRequiredConfig_clone(t *testing.T) {
+	c := new(requiredConfig)
+	if err := c.addRequiredField("pkg.User.ID"); err != nil {
+		t.Fatal(err)
+	}
+
+	clone := c.clone()
+	if err := c.addRequiredField("pkg.User.Name"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.addExempt([]string{"pkg/gen"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := clone.RequiredFields("pkg", "User"), []string{"ID"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("clone fields = %v, want %v", got, want)
+	}
+	if clone.IsExempt("pkg/gen") {
+		t.Errorf("clone should not be affected by changes to the original")
+	}
+}
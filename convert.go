@@ -0,0 +1,70 @@
+package requiredfield
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+)
+
+// visitConversion checks a conversion to a struct type
+// that has required fields, e.g.
+//
+//	User(legacyUser)
+//
+// Conversions copy fields between struct types with the same layout,
+// so if the source type doesn't require a field that the target does,
+// the converted value may have it unset.
+// Calls that are not conversions are ignored.
+func (e *enforcer) visitConversion(call *ast.CallExpr, stack []ast.Node) {
+	if len(call.Args) != 1 {
+		return
+	}
+	if tv, ok := e.Info.Types[call.Fun]; !ok || !tv.IsType() {
+		return
+	}
+
+	target := derefAlias(e.Info.TypeOf(call))
+	source := derefAlias(e.Info.TypeOf(call.Args[0]))
+	if target == nil || source == nil || types.Identical(target, source) {
+		return
+	}
+	if _, ok := source.Underlying().(*types.Struct); !ok {
+		// Type parameters may be any of several types,
+		// so there's nothing they're guaranteed to set.
+		return
+	}
+
+	unset := e.requiredFields(target, stack)
+	if len(unset) == 0 {
+		return
+	}
+
+	// As with literals, 'return T(v), err' is allowed
+	// for failures.
+	if len(stack) > 1 && isReturnedWithNonNilError(stack) {
+		return
+	}
+
+	// Fields required by the source type
+	// are guaranteed to be set in the converted value.
+	for name := range e.requiredFields(source, nil) {
+		delete(unset, name)
+	}
+	for name, class := range unset {
+		class.Category = categoryConversion
+		unset[name] = class
+	}
+
+	prefix := fmt.Sprintf("conversion from %v may not set required fields: ",
+		types.TypeString(source, e.qualifier))
+	e.reportMissing(call.Lparen, unset, prefix)
+}
+
+// qualifier qualifies names of types from packages other than the one
+// being checked with their package names.
+func (e *enforcer) qualifier(pkg *types.Package) string {
+	if pkg.Path() == e.PkgPath {
+		return ""
+	}
+	return pkg.Name()
+}
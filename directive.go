@@ -0,0 +1,240 @@
+package requiredfield
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// _directivePrefix is the prefix of directive comments,
+// e.g. "//requiredfield:option group=logger".
+// Like other Go directives, there's no space after "//".
+const _directivePrefix = "//requiredfield:"
+
+// directive is a "//requiredfield:" comment on a declaration.
+type directive struct {
+	Name string // e.g. "option"
+	Args string // e.g. "group=logger"
+}
+
+// directives returns the directives in a doc comment.
+func directives(doc *ast.CommentGroup) []directive {
+	if doc == nil {
+		return nil
+	}
+
+	var ds []directive
+	for _, c := range doc.List {
+		text, ok := strings.CutPrefix(c.Text, _directivePrefix)
+		if !ok {
+			continue
+		}
+		name, args, _ := strings.Cut(text, " ")
+		ds = append(ds, directive{
+			Name: name,
+			Args: strings.TrimSpace(args),
+		})
+	}
+	return ds
+}
+
+// directiveNames splits the arguments of a directive
+// into a list of names separated by spaces or commas.
+func directiveNames(args string) []string {
+	return strings.FieldsFunc(args, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
+
+// funcDecl exports facts for directives on a function declaration.
+func (f *finder) funcDecl(decl *ast.FuncDecl) {
+	ds := directives(decl.Doc)
+	if len(ds) == 0 {
+		return
+	}
+
+	fn, ok := f.Info.Defs[decl.Name].(*types.Func)
+	if !ok {
+		f.Reportf(decl.Name.Pos(), "could not find object for %v", decl.Name)
+		return
+	}
+
+	var (
+		requires, calls []string
+		params          = make(map[int]*requiredParam)
+	)
+	for _, d := range ds {
+		var err error
+		switch d.Name {
+		case "option":
+			err = f.optionDirective(fn, d)
+
+		case "requires-option":
+			var groups []string
+			groups, err = requiresOptionDirective(fn, d)
+			requires = append(requires, groups...)
+
+		case "requires-call":
+			var methods []string
+			methods, err = requiresCallDirective(fn, d)
+			calls = append(calls, methods...)
+
+		case "param":
+			var param *requiredParam
+			param, err = paramDirective(fn, d)
+			if err == nil {
+				if p, ok := params[param.Index]; ok {
+					p.Fields = append(p.Fields, param.Fields...)
+				} else {
+					params[param.Index] = param
+				}
+			}
+
+		default:
+			err = errors.New("unknown directive")
+		}
+		if err != nil {
+			// Report at the function name
+			// since directive comments can't have trailing text.
+			f.Reportf(decl.Name.Pos(), "invalid requiredfield:%v directive: %v", d.Name, err)
+		}
+	}
+
+	if len(requires) > 0 {
+		slices.Sort(requires)
+		f.ExportObjectFact(fn, &requiresOptions{
+			Groups: slices.Compact(requires),
+		})
+	}
+	if len(calls) > 0 {
+		slices.Sort(calls)
+		f.ExportObjectFact(fn, &requiresCalls{
+			Methods: slices.Compact(calls),
+		})
+	}
+	if len(params) > 0 {
+		var fact requiresParamFields
+		for _, idx := range slices.Sorted(maps.Keys(params)) {
+			p := params[idx]
+			slices.Sort(p.Fields)
+			p.Fields = slices.Compact(p.Fields)
+			fact.Params = append(fact.Params, *p)
+		}
+		f.ExportObjectFact(fn, &fact)
+	}
+}
+
+// optionDirective handles "//requiredfield:option group=NAME"
+// on a function that returns an option.
+func (f *finder) optionDirective(fn *types.Func, d directive) error {
+	var group string
+	for _, arg := range strings.Fields(d.Args) {
+		key, value, _ := strings.Cut(arg, "=")
+		switch key {
+		case "group":
+			group = value
+		default:
+			return fmt.Errorf("unknown argument %q", arg)
+		}
+	}
+
+	switch {
+	case group == "":
+		return errors.New("expected group=NAME")
+	case fn.Signature().Results().Len() == 0:
+		return errors.New("function does not return an option")
+	}
+
+	f.ExportObjectFact(fn, &isOption{Group: group})
+	return nil
+}
+
+// requiresOptionDirective handles "//requiredfield:requires-option NAME..."
+// on a variadic function that accepts options,
+// returning the option groups that calls must include.
+func requiresOptionDirective(fn *types.Func, d directive) ([]string, error) {
+	groups := directiveNames(d.Args)
+	switch {
+	case len(groups) == 0:
+		return nil, errors.New("expected one or more option groups")
+	case !fn.Signature().Variadic():
+		return nil, errors.New("function is not variadic")
+	}
+	return groups, nil
+}
+
+// requiresCallDirective handles "//requiredfield:requires-call NAME..."
+// on a method of a builder type,
+// returning the methods that must be called on the builder before it.
+func requiresCallDirective(fn *types.Func, d directive) ([]string, error) {
+	methods := directiveNames(d.Args)
+	recv := fn.Signature().Recv()
+	switch {
+	case len(methods) == 0:
+		return nil, errors.New("expected one or more method names")
+	case recv == nil:
+		return nil, errors.New("function is not a method")
+	}
+
+	// Methods with pointer receivers are in the method set
+	// of the pointer type only.
+	typ := recv.Type()
+	if _, ok := typ.(*types.Pointer); !ok {
+		typ = types.NewPointer(typ)
+	}
+	mset := types.NewMethodSet(typ)
+	for _, name := range methods {
+		if mset.Lookup(fn.Pkg(), name) == nil {
+			return nil, fmt.Errorf("unknown method %q", name)
+		}
+	}
+	return methods, nil
+}
+
+// paramDirective handles "//requiredfield:param NAME FIELD,..."
+// on a function that accepts a struct as the parameter NAME.
+// Struct literals passed directly as the parameter must set the fields.
+func paramDirective(fn *types.Func, d directive) (*requiredParam, error) {
+	name, rest, _ := strings.Cut(d.Args, " ")
+	fields := directiveNames(rest)
+	if name == "" || len(fields) == 0 {
+		return nil, errors.New("expected PARAM FIELD,...")
+	}
+
+	sig := fn.Signature()
+	params := sig.Params()
+	idx := -1
+	for i := range params.Len() {
+		if params.At(i).Name() == name {
+			idx = i
+			break
+		}
+	}
+	switch {
+	case idx < 0:
+		return nil, fmt.Errorf("no parameter named %q", name)
+	case sig.Variadic() && idx == params.Len()-1:
+		return nil, fmt.Errorf("parameter %q is variadic", name)
+	}
+
+	typ := derefAlias(params.At(idx).Type())
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("parameter %q is not a struct", name)
+	}
+	for _, field := range fields {
+		if fieldByName(st, field) == nil {
+			return nil, fmt.Errorf("field %v does not exist in %v", field, typ)
+		}
+	}
+
+	return &requiredParam{
+		Index:  idx,
+		Name:   name,
+		Fields: fields,
+	}, nil
+}
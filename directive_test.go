@@ -0,0 +1,51 @@
+package requiredfield
+
+import (
+	"go/ast"
+	"reflect"
+	"testing"
+)
+
+func TestDirectives(t *testing.T) {
+	doc := &ast.CommentGroup{
+		List: []*ast.Comment{
+			{Text: "// New builds a server."},
+			{Text: "//"},
+			{Text: "//requiredfield:requires-option logger, port"},
+			{Text: "// requiredfield:option group=ignored"},
+			{Text: "//requiredfield:option  group=logger "},
+			{Text: "//go:noinline"},
+		},
+	}
+
+	want := []directive{
+		{Name: "requires-option", Args: "logger, port"},
+		{Name: "option", Args: "group=logger"},
+	}
+	if got := directives(doc); !reflect.DeepEqual(got, want) {
+		t.Errorf("directives() = %q, want %q", got, want)
+	}
+
+	if got := directives(nil); got != nil {
+		t.Errorf("directives(nil) = %q, want nil", got)
+	}
+}
+
+func TestDirectiveNames(t *testing.T) {
+	tests := []struct {
+		give string
+		want []string
+	}{
+		{"", []string{}},
+		{"logger", []string{"logger"}},
+		{"logger port", []string{"logger", "port"}},
+		{"logger,port", []string{"logger", "port"}},
+		{"logger, port", []string{"logger", "port"}},
+	}
+
+	for _, tt := range tests {
+		if got := directiveNames(tt.give); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("directiveNames(%q) = %q, want %q", tt.give, got, tt.want)
+		}
+	}
+}
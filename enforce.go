@@ -4,6 +4,8 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
 
@@ -12,11 +14,31 @@ import (
 )
 
 type enforcer struct {
-	Info *types.Info // required
+	Info    *types.Info // required
+	PkgPath string      // required: import path of the package being checked
 
 	ImportObjectFact func(obj types.Object, fact analysis.Fact) bool // required
-	Reportf          func(pos token.Pos, msg string, args ...any)    // required
+	Report           func(analysis.Diagnostic)                       // required
 	Config           *requiredConfig
+
+	// Conversions enables checks on conversions to struct types
+	// from types that don't require the same fields.
+	Conversions bool
+
+	// Strict enables checks on zero values of struct types
+	// with required fields created by make and arrays.
+	Strict bool
+
+	// Partial allows functions to return literals
+	// that are missing required fields
+	// if callers set those fields before using the results.
+	// ExportObjectFact is required if this is set.
+	Partial          bool
+	ExportObjectFact func(obj types.Object, fact analysis.Fact)
+
+	// partial records fields that may be missing
+	// from the first result of functions in this package.
+	partial map[*types.Func]map[string]struct{}
 }
 
 var _enforceNodeFilter = []ast.Node{
@@ -24,68 +46,49 @@ var _enforceNodeFilter = []ast.Node{
 }
 
 func (e *enforcer) Enforce(inspect *inspector.Inspector) {
-	inspect.WithStack(_enforceNodeFilter, func(n ast.Node, push bool, stack []ast.Node) (proceed bool) {
+	filter := append(slices.Clone(_enforceNodeFilter), new(ast.CallExpr))
+	if e.Strict {
+		filter = append(filter, new(ast.ValueSpec))
+	}
+
+	inspect.WithStack(filter, func(n ast.Node, push bool, stack []ast.Node) (proceed bool) {
 		if !push {
 			return true
 		}
 
-		e.visit(n, stack)
-		return true
-	})
-}
-
-func (e *enforcer) visit(n ast.Node, stack []ast.Node) {
-	lit := n.(*ast.CompositeLit)
-	typ := e.Info.TypeOf(lit)
-
-	if ptr, ok := typ.(*types.Pointer); ok {
-		typ = ptr.Elem()
-	}
-	if alias, ok := typ.(*types.Alias); ok {
-		typ = types.Unalias(alias)
-	}
-
-	var unset map[string]struct{} // required fields that are not set
-	switch typ := typ.(type) {
-	case *types.Named:
-		// named struct (probably)
-
-		var reqFields hasRequiredFields
-		if e.ImportObjectFact(typ.Obj(), &reqFields) && len(reqFields.List) > 0 {
-			unset = make(map[string]struct{}, len(reqFields.List))
-			for _, name := range reqFields.List {
-				unset[name] = struct{}{}
+		switch n := n.(type) {
+		case *ast.CompositeLit:
+			e.visit(n, stack)
+			if e.Strict {
+				e.visitArrayLit(n)
 			}
-		}
-
-		// If there are any configured required fields,
-		// add them to the unset map.
-		//
-		// (Only named structs can have configured required fields.)
-		pkgPath := typ.Obj().Pkg().Path()
-		typeName := typ.Obj().Name()
-		configFields := e.Config.RequiredFields(pkgPath, typeName)
-		for _, name := range configFields {
-			if unset == nil {
-				unset = make(map[string]struct{}, len(configFields))
+		case *ast.CallExpr:
+			e.visitOptions(n)
+			e.visitBuild(n, stack)
+			if e.Conversions {
+				e.visitConversion(n, stack)
 			}
-			unset[name] = struct{}{}
-		}
-
-	case *types.Struct:
-		// anonymous struct
-		var fact isRequiredField
-		for i := 0; i < typ.NumFields(); i++ {
-			f := typ.Field(i)
-			if e.ImportObjectFact(f, &fact) {
-				if unset == nil {
-					unset = make(map[string]struct{})
-				}
-				unset[f.Name()] = struct{}{}
+			if e.Strict {
+				e.visitMake(n)
 			}
+		case *ast.ValueSpec:
+			e.visitVarSpec(n)
 		}
+		return true
+	})
+
+	if e.Partial {
+		e.checkPartial(inspect)
 	}
+}
 
+func (e *enforcer) visit(lit *ast.CompositeLit, stack []ast.Node) {
+	typ := derefAlias(e.Info.TypeOf(lit))
+
+	// Required fields that are not set,
+	// and how to report them if they're missing.
+	unset := e.requiredFields(typ, stack)
+	unset = e.addParamFields(unset, lit, stack)
 	if len(unset) == 0 {
 		// Type has no required fields, or is not a struct.
 		return
@@ -99,7 +102,7 @@ func (e *enforcer) visit(n ast.Node, stack []ast.Node) {
 	// and the last value of that return statement is an error
 	// that is not explicitly set to "nil",
 	// we will not enforce required fields on that struct literal.
-	if len(stack) > 1 && e.isReturnedWithNonNilError(stack) {
+	if len(stack) > 1 && isReturnedWithNonNilError(stack) {
 		// The struct literal is part of a return statement
 		// that has a non-nil error as its last return value.
 		return
@@ -129,13 +132,404 @@ func (e *enforcer) visit(n ast.Node, stack []ast.Node) {
 		return
 	}
 
-	var missing []string
-	for f := range unset {
-		missing = append(missing, f)
+	if e.Partial {
+		if fn, ok := e.partialReturn(lit, stack); ok {
+			// Callers are checked instead.
+			e.addPartial(fn, slices.Collect(maps.Keys(unset)))
+			return
+		}
+	}
+
+	e.reportMissing(lit.Lbrace, unset, "missing required fields: ")
+}
+
+// reportMissing reports missing required fields at pos
+// grouped by how they should be reported, most severe first.
+// Each message is the prefix followed by the names of the fields.
+func (e *enforcer) reportMissing(pos token.Pos, unset map[string]diagClass, prefix string) {
+	missingByClass := make(map[diagClass][]string)
+	for f, class := range unset {
+		missingByClass[class] = append(missingByClass[class], f)
+	}
+	classes := slices.SortedFunc(maps.Keys(missingByClass), diagClass.Compare)
+	for _, class := range classes {
+		missing := missingByClass[class]
+		sort.Strings(missing)
+
+		msg := prefix + strings.Join(missing, ", ")
+		if class.Severity != severityError {
+			msg = class.Severity.String() + ": " + msg
+		}
+
+		e.Report(analysis.Diagnostic{
+			Pos:      pos,
+			Category: class.Category,
+			Message:  msg,
+		})
+	}
+}
+
+// requiredFields returns the required fields of a type
+// and how to report them if they're missing.
+// It returns nil if the type has no required fields or is not a struct.
+//
+// stack is the path to the node that produces a value of the type.
+func (e *enforcer) requiredFields(
+	typ types.Type,
+	stack []ast.Node,
+) map[string]diagClass {
+	var unset map[string]diagClass
+	switch typ := typ.(type) {
+	case *types.Named:
+		// named struct (probably)
+
+		var reqFields hasRequiredFields
+		if e.ImportObjectFact(typ.Obj(), &reqFields) && len(reqFields.List) > 0 {
+			unset = make(map[string]diagClass, len(reqFields.List))
+			for _, name := range reqFields.List {
+				unset[name] = defaultClass
+			}
+		}
+
+		// If there are any configured required fields,
+		// add them to the unset map.
+		st, _ := typ.Underlying().(*types.Struct)
+		unset = e.addConfigFields(unset, st, namedTypeSpecs(typ)...)
+
+	case *types.Struct:
+		// anonymous struct
+		var fact isRequiredField
+		for i := 0; i < typ.NumFields(); i++ {
+			f := typ.Field(i)
+			if e.ImportObjectFact(f, &fact) {
+				if unset == nil {
+					unset = make(map[string]diagClass)
+				}
+				unset[f.Name()] = defaultClass
+			}
+		}
+
+		// Anonymous structs can have configured required fields
+		// only if they're nested inside a named struct.
+		unset = e.addConfigFields(unset, typ, e.nestedTypeSpecs(stack)...)
+
+	case *types.TypeParam:
+		// Literals of a type parameter are allowed
+		// only if all types in its type set have the same struct type.
+		// The literal may produce any of these types,
+		// so fields required by any of them must be set.
+		for _, term := range typeTerms(typ) {
+			for name, class := range e.requiredFields(types.Unalias(term), stack) {
+				if unset == nil {
+					unset = make(map[string]diagClass)
+				}
+				if old, ok := unset[name]; !ok || class.Compare(old) < 0 {
+					unset[name] = class
+				}
+			}
+		}
+	}
+
+	return unset
+}
+
+// addConfigFields adds fields configured as required for the given types
+// to the unset map, allocating it if necessary.
+//
+// st is the struct type, or nil if the type is not a struct.
+// Configured fields that do not exist in st are skipped.
+func (e *enforcer) addConfigFields(
+	unset map[string]diagClass,
+	st *types.Struct,
+	specs ...typeSpec,
+) map[string]diagClass {
+	for _, ts := range specs {
+		for configured, rule := range e.Config.fieldsOf(ts, e.PkgPath) {
+			name, ok := rule.FieldName(st, configured)
+			if !ok {
+				// Reported by the package that declares the type.
+				continue
+			}
+
+			if unset == nil {
+				unset = make(map[string]diagClass)
+			}
+
+			// If the field is required more than once,
+			// report it with the most severe class.
+			class := rule.Class()
+			if old, ok := unset[name]; !ok || class.Compare(old) < 0 {
+				unset[name] = class
+			}
+		}
+	}
+	return unset
+}
+
+// fieldByName returns the field of st with the given name,
+// or nil if there isn't one or st is nil.
+// Embedded fields are not searched.
+func fieldByName(st *types.Struct, name string) *types.Var {
+	if st == nil {
+		return nil
+	}
+	for i := range st.NumFields() {
+		if f := st.Field(i); f.Name() == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// namedTypeSpecs returns the typeSpecs that match a named type.
+//
+// For instantiated generic types, this includes a typeSpec
+// for the generic type and one for this specific instantiation.
+// It returns nil for types that don't belong to a package,
+// e.g. the predeclared error type.
+func namedTypeSpecs(typ *types.Named) []typeSpec {
+	obj := typ.Obj()
+	if obj.Pkg() == nil {
+		return nil
+	}
+
+	ts := typeSpec{
+		packagePath: obj.Pkg().Path(),
+		typeName:    obj.Name(),
+	}
+
+	targs := typ.TypeArgs()
+	if targs.Len() == 0 {
+		return []typeSpec{ts}
+	}
+
+	args := make([]string, targs.Len())
+	for i := range targs.Len() {
+		args[i] = types.TypeString(types.Unalias(targs.At(i)), nil)
+	}
+	instance := ts
+	instance.typeArgs = normalizeTypeArgs(strings.Join(args, ","))
+	return []typeSpec{ts, instance}
+}
+
+// nestedTypeSpec returns the typeSpec for an anonymous struct literal
+// (the last node in the stack)
+// that is nested inside the literal of a named struct.
+// For example, given:
+//
+//	type Outer struct {
+//		Inner struct{ Field int }
+//	}
+//
+// The literal in the following is reached through Outer.Inner:
+//
+//	Outer{Inner: struct{ Field int }{}}
+//
+// Elements of slices, arrays, and maps held in fields
+// are reached through the field that holds them.
+//
+// It returns nil if the literal is not nested inside a named struct
+// or if the stack is empty.
+func (e *enforcer) nestedTypeSpecs(stack []ast.Node) []typeSpec {
+	if len(stack) == 0 {
+		return nil
+	}
+
+	var (
+		path  []string // field names, innermost first
+		child = stack[len(stack)-1]
+	)
+	for idx := len(stack) - 2; idx >= 0; idx-- {
+		switch n := stack[idx].(type) {
+		case *ast.ParenExpr, *ast.UnaryExpr:
+			// &struct{...}{...}
+
+		case *ast.KeyValueExpr:
+			// Field name or map key/index of the parent literal.
+			// This will be resolved when we reach the parent.
+
+		case *ast.CompositeLit:
+			var st *types.Struct
+			switch typ := derefAlias(e.Info.TypeOf(n)).(type) {
+			case *types.Named:
+				st, _ = typ.Underlying().(*types.Struct)
+				if st == nil {
+					// Named slices, maps, etc. don't have fields.
+					return nil
+				}
+
+				name, ok := structFieldName(st, n, child)
+				if !ok {
+					return nil
+				}
+				path = append(path, name)
+
+				specs := namedTypeSpecs(typ)
+				for i := range specs {
+					for j := len(path) - 1; j >= 0; j-- {
+						specs[i] = specs[i].nested(path[j])
+					}
+				}
+				return specs
+
+			case *types.Struct:
+				name, ok := structFieldName(typ, n, child)
+				if !ok {
+					return nil
+				}
+				path = append(path, name)
+
+			case *types.Slice, *types.Array, *types.Map:
+				// Element of a collection.
+				// Keep going up to the field that holds it.
+
+			default:
+				return nil
+			}
+
+		default:
+			return nil
+		}
+
+		child = stack[idx]
 	}
-	sort.Strings(missing)
 
-	e.Reportf(lit.Lbrace, "missing required fields: %s", strings.Join(missing, ", "))
+	return nil
+}
+
+// structFieldName returns the name of the field of st
+// that is set to child in the struct literal lit.
+// child is an element of lit.Elts.
+func structFieldName(st *types.Struct, lit *ast.CompositeLit, child ast.Node) (string, bool) {
+	for i, elt := range lit.Elts {
+		if elt != child {
+			continue
+		}
+
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if id, ok := kv.Key.(*ast.Ident); ok {
+				return id.Name, true
+			}
+			return "", false
+		}
+
+		// Unkeyed struct literal.
+		if i < st.NumFields() {
+			return st.Field(i).Name(), true
+		}
+	}
+
+	return "", false
+}
+
+// typeTerms returns the types in the type set
+// of a type parameter's constraint.
+// It returns nil if the constraint doesn't restrict the type set
+// to specific types, e.g. for "any" or method-only interfaces.
+func typeTerms(tp *types.TypeParam) []types.Type {
+	iface, ok := tp.Constraint().Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+	return interfaceTerms(iface)
+}
+
+// interfaceTerms returns the types in the type set of an interface,
+// or nil if it isn't restricted to specific types.
+//
+// The type set of an interface is the intersection
+// of the type sets of its embedded elements,
+// e.g. "interface{ A | B; B | C }" only permits B.
+func interfaceTerms(iface *types.Interface) []types.Type {
+	var (
+		terms      []*types.Term
+		restricted bool
+	)
+	for i := range iface.NumEmbeddeds() {
+		elemTerms := embeddedTerms(iface.EmbeddedType(i))
+		if elemTerms == nil {
+			continue // e.g. method-only interfaces
+		}
+		if !restricted {
+			terms, restricted = elemTerms, true
+			continue
+		}
+
+		var both []*types.Term
+		for _, x := range terms {
+			for _, y := range elemTerms {
+				if t, ok := intersectTerms(x, y); ok {
+					both = append(both, t)
+				}
+			}
+		}
+		terms = both
+	}
+
+	if !restricted {
+		return nil
+	}
+	typs := make([]types.Type, len(terms))
+	for i, t := range terms {
+		typs[i] = t.Type()
+	}
+	return typs
+}
+
+// embeddedTerms returns the terms of an element embedded in an interface,
+// or nil if it isn't restricted to specific types.
+func embeddedTerms(typ types.Type) []*types.Term {
+	var terms []*types.Term
+	addTerm := func(t *types.Term) bool {
+		inner, ok := t.Type().Underlying().(*types.Interface)
+		if !ok {
+			terms = append(terms, t)
+			return true
+		}
+
+		innerTypes := interfaceTerms(inner)
+		for _, typ := range innerTypes {
+			terms = append(terms, types.NewTerm(false, typ))
+		}
+		return innerTypes != nil
+	}
+
+	if union, ok := typ.(*types.Union); ok {
+		for i := range union.Len() {
+			// A union with an unrestricted interface
+			// is unrestricted too.
+			if !addTerm(union.Term(i)) {
+				return nil
+			}
+		}
+		return terms
+	}
+
+	addTerm(types.NewTerm(false, typ))
+	return terms
+}
+
+// intersectTerms returns the intersection of two terms
+// if it isn't empty.
+// Tilde terms (~T) include all types with the underlying type T.
+func intersectTerms(x, y *types.Term) (*types.Term, bool) {
+	switch {
+	case x.Tilde() && !y.Tilde():
+		x, y = y, x
+	case x.Tilde() == y.Tilde():
+		return x, types.Identical(x.Type(), y.Type())
+	}
+
+	// x is a single type and y is a tilde term.
+	return x, types.Identical(x.Type().Underlying(), y.Type())
+}
+
+// derefAlias removes pointers and aliases from the given type.
+func derefAlias(typ types.Type) types.Type {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	return types.Unalias(typ)
 }
 
 // isReturnedWithNonNilError reports whether target is part of a return
@@ -147,7 +541,7 @@ func (e *enforcer) visit(n ast.Node, stack []ast.Node) {
 // it should still be checked for required fields, e.g.
 //
 //	return nil, &MyError{...} // and MyError has required fields
-func (e *enforcer) isReturnedWithNonNilError(stack []ast.Node) bool {
+func isReturnedWithNonNilError(stack []ast.Node) bool {
 	// Find the nearest return statement.
 	var retStmt *ast.ReturnStmt
 	retIdx := -1
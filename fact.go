@@ -33,3 +33,99 @@ func (*isRequiredField) AFact() {}
 func (f *isRequiredField) String() string {
 	return "required"
 }
+
+// partialResult is a Fact attached to functions
+// whose first result may be missing required fields.
+// Callers must set these fields before using the result.
+type partialResult struct {
+	// List is a sorted list of field names
+	// that may be missing from the result.
+	List []string
+}
+
+var _ analysis.Fact = (*partialResult)(nil)
+
+func (*partialResult) AFact() {}
+
+func (f *partialResult) String() string {
+	return "partial<" + strings.Join(f.List, ", ") + ">"
+}
+
+// isOption is a Fact attached to functions that return an option
+// for a variadic function, marked with an "option" directive.
+type isOption struct {
+	// Group is the name of the group of options
+	// that this option belongs to.
+	Group string
+}
+
+var _ analysis.Fact = (*isOption)(nil)
+
+func (*isOption) AFact() {}
+
+func (f *isOption) String() string {
+	return "option<" + f.Group + ">"
+}
+
+// requiresOptions is a Fact attached to variadic functions
+// that require options from certain groups,
+// marked with "requires-option" directives.
+type requiresOptions struct {
+	// Groups is a sorted list of option groups
+	// that calls to the function must include.
+	Groups []string
+}
+
+var _ analysis.Fact = (*requiresOptions)(nil)
+
+func (*requiresOptions) AFact() {}
+
+func (f *requiresOptions) String() string {
+	return "requires-option<" + strings.Join(f.Groups, ", ") + ">"
+}
+
+// requiresCalls is a Fact attached to terminal methods of builder types,
+// e.g. Build, that require other methods to be called first,
+// marked with "requires-call" directives.
+type requiresCalls struct {
+	// Methods is a sorted list of methods
+	// that must be called on the builder first.
+	Methods []string
+}
+
+var _ analysis.Fact = (*requiresCalls)(nil)
+
+func (*requiresCalls) AFact() {}
+
+func (f *requiresCalls) String() string {
+	return "requires-call<" + strings.Join(f.Methods, ", ") + ">"
+}
+
+// requiresParamFields is a Fact attached to functions
+// that require fields of struct literals passed as parameters,
+// marked with "param" directives.
+type requiresParamFields struct {
+	// Params lists the parameters with required fields
+	// in the order they're declared.
+	Params []requiredParam
+}
+
+// requiredParam is a parameter whose struct type
+// has fields required by a "param" directive.
+type requiredParam struct {
+	Index  int    // index of the parameter in the signature
+	Name   string // name of the parameter
+	Fields []string
+}
+
+var _ analysis.Fact = (*requiresParamFields)(nil)
+
+func (*requiresParamFields) AFact() {}
+
+func (f *requiresParamFields) String() string {
+	params := make([]string, len(f.Params))
+	for i, p := range f.Params {
+		params[i] = p.Name + ": " + strings.Join(p.Fields, ", ")
+	}
+	return "param<" + strings.Join(params, "; ") + ">"
+}
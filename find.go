@@ -18,11 +18,13 @@ type finder struct {
 
 	ExportObjectFact func(obj types.Object, fact analysis.Fact)   // required
 	Reportf          func(pos token.Pos, msg string, args ...any) // required
+	Config           *requiredConfig
 }
 
 var _finderNodeFilter = []ast.Node{
 	new(ast.TypeSpec),
 	new(ast.StructType),
+	new(ast.FuncDecl),
 }
 
 func (f *finder) Find(inspect *inspector.Inspector) {
@@ -42,6 +44,9 @@ func (f *finder) Find(inspect *inspector.Inspector) {
 			}
 		case *ast.StructType:
 			st = n
+		case *ast.FuncDecl:
+			f.funcDecl(n)
+			return
 		}
 
 		// If the type spec is not a struct, or if we've already seen it,
@@ -70,20 +75,7 @@ func (f *finder) structType(name *ast.Ident, t *ast.StructType) {
 	)
 	st := f.Info.TypeOf(t).(*types.Struct)
 	for i, field := range t.Fields.List {
-		if field.Comment == nil {
-			continue
-		}
-
-		var found bool
-		fieldLine := file.Line(field.End())
-		for _, c := range field.Comment.List {
-			if file.Line(c.Pos()) == fieldLine && isRequiredComment(c) {
-				found = true
-				break
-			}
-		}
-
-		if !found {
+		if !f.isRequired(file, field) {
 			continue
 		}
 
@@ -129,8 +121,44 @@ func (f *finder) structType(name *ast.Ident, t *ast.StructType) {
 	}
 }
 
+// isRequired reports whether a field is marked as required
+// with a "// required" comment or a configured struct tag.
+func (f *finder) isRequired(file *token.File, field *ast.Field) bool {
+	if _, ok := requiredComment(file, field); ok {
+		return true
+	}
+	_, ok := f.Config.requiredTagOf(field)
+	return ok
+}
+
+// requiredComment returns the "// required" comment
+// on the same line as the end of the field, if any.
+func requiredComment(file *token.File, field *ast.Field) (*ast.Comment, bool) {
+	if field.Comment == nil {
+		return nil, false
+	}
+
+	fieldLine := file.Line(field.End())
+	for _, c := range field.Comment.List {
+		if file.Line(c.Pos()) == fieldLine && isRequiredComment(c) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
 const _required = "required"
 
+// requiredDescription returns the description following "required"
+// in a comment that satisfies isRequiredComment.
+// For example, "// required: must be positive" has the description
+// "must be positive".
+func requiredDescription(c *ast.Comment) string {
+	text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+	text = strings.TrimPrefix(text, _required)
+	return strings.TrimSpace(strings.TrimLeft(text, ":-,;. \t"))
+}
+
 func isRequiredComment(c *ast.Comment) bool {
 	text, ok := strings.CutPrefix(c.Text, "//")
 	if !ok {
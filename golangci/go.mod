@@ -0,0 +1,16 @@
+module go.abhg.dev/requiredfield/golangci
+
+go 1.25.0
+
+require (
+	github.com/golangci/plugin-module-register v0.1.2
+	go.abhg.dev/requiredfield v0.0.0-00010101000000-000000000000
+	golang.org/x/tools v0.42.0
+)
+
+require (
+	golang.org/x/mod v0.33.0 // indirect
+	golang.org/x/sync v0.19.0 // indirect
+)
+
+replace go.abhg.dev/requiredfield => ../
@@ -0,0 +1,147 @@
+// Package golangci registers requiredfield as a golangci-lint module plugin.
+//
+// To use it, reference this package in the .custom-gcl.yml
+// used to build a custom golangci-lint binary:
+//
+//	plugins:
+//	  - module: go.abhg.dev/requiredfield
+//	    import: go.abhg.dev/requiredfield/golangci
+//	    version: latest
+//
+// And enable the linter in .golangci.yml with optional settings:
+//
+//	linters:
+//	  enable:
+//	    - requiredfield
+//	  settings:
+//	    custom:
+//	      requiredfield:
+//	        type: module
+//	        settings:
+//	          config: requiredfield.rc
+//	          required:
+//	            - net/http.Request.Method
+//	          exempt:
+//	            - example.com/myapp/gen/...
+//	          tags:
+//	            - validate:required
+//	          rules:
+//	            - type: example.com/myapp.Request
+//	              fields: [UserID]
+//	              severity: warning
+package golangci
+
+import (
+	"fmt"
+
+	"github.com/golangci/plugin-module-register/register"
+	"go.abhg.dev/requiredfield"
+	"golang.org/x/tools/go/analysis"
+)
+
+func init() {
+	register.Plugin("requiredfield", New)
+}
+
+// Settings are the settings accepted by the plugin
+// under the linter's "settings" key.
+type Settings struct {
+	// Config is the path to a configuration file
+	// in the requiredfield.rc or JSON format.
+	// Relative paths are resolved from the working directory
+	// of golangci-lint.
+	//
+	// This is the same as the -config flag.
+	Config string `json:"config"`
+
+	// FileConfig holds configuration in the same form
+	// as a JSON configuration file:
+	// "include", "required", "exempt", "tags", "rules", and "schemas".
+	// Relative paths are resolved from the working directory
+	// of golangci-lint.
+	//
+	// This is applied after the file specified by Config.
+	requiredfield.FileConfig
+
+	// Promote reports optional fields with a fix
+	// to mark them as required.
+	//
+	// This is the same as the -promote flag.
+	Promote bool `json:"promote"`
+
+	// Conversions reports conversions to struct types
+	// from types that don't require the same fields.
+	//
+	// This is the same as the -conversions flag.
+	Conversions bool `json:"conversions"`
+
+	// Strict reports zero values of struct types with required fields
+	// created by make and arrays.
+	//
+	// This is the same as the -strict flag.
+	Strict bool `json:"strict"`
+
+	// Partial allows functions to return values
+	// that are missing required fields
+	// if their callers set those fields before using them.
+	//
+	// This is the same as the -partial flag.
+	Partial bool `json:"partial"`
+}
+
+// config builds a requiredfield configuration from the settings.
+func (s *Settings) config() (*requiredfield.Config, error) {
+	var cfg requiredfield.Config
+	if s.Config != "" {
+		if err := cfg.LoadFile(s.Config); err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+	}
+
+	if err := cfg.Apply(s.FileConfig); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// plugin is the golangci-lint module plugin for requiredfield.
+type plugin struct {
+	settings Settings
+}
+
+var _ register.LinterPlugin = (*plugin)(nil)
+
+// New builds a requiredfield plugin from golangci-lint settings.
+// It is registered under the name "requiredfield".
+func New(settings any) (register.LinterPlugin, error) {
+	s, err := register.DecodeSettings[Settings](settings)
+	if err != nil {
+		return nil, fmt.Errorf("requiredfield: %w", err)
+	}
+	return &plugin{settings: s}, nil
+}
+
+// BuildAnalyzers returns the requiredfield analyzer
+// configured with the plugin settings.
+func (p *plugin) BuildAnalyzers() ([]*analysis.Analyzer, error) {
+	cfg, err := p.settings.config()
+	if err != nil {
+		return nil, fmt.Errorf("requiredfield: %w", err)
+	}
+
+	return []*analysis.Analyzer{
+		requiredfield.New(requiredfield.Options{
+			Config:      cfg,
+			Promote:     p.settings.Promote,
+			Conversions: p.settings.Conversions,
+			Strict:      p.settings.Strict,
+			Partial:     p.settings.Partial,
+		}),
+	}, nil
+}
+
+// GetLoadMode reports that the analyzer needs type information.
+func (*plugin) GetLoadMode() string {
+	return register.LoadModeTypesInfo
+}
@@ -0,0 +1,187 @@
+package golangci
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golangci/plugin-module-register/register"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestPlugin(t *testing.T) {
+	rcPath := filepath.Join(t.TempDir(), "requiredfield.rc")
+	rc := strings.Join([]string{
+		"required external.User.ID",
+		"required external.User.Name",
+	}, "\n")
+	if err := os.WriteFile(rcPath, []byte(rc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	newPlugin, err := register.GetPlugin("requiredfield")
+	if err != nil {
+		t.Fatalf("plugin not registered: %v", err)
+	}
+
+	// Settings as decoded from .golangci.yml.
+	plugin, err := newPlugin(map[string]any{
+		"config": rcPath,
+		"required": []any{
+			"external.Config.APIKey",
+			"fields_from_config.LocalType.B",
+		},
+		"exempt": []any{"example.com/gen/..."},
+	})
+	if err != nil {
+		t.Fatalf("failed to build plugin: %v", err)
+	}
+
+	if got := plugin.GetLoadMode(); got != register.LoadModeTypesInfo {
+		t.Errorf("GetLoadMode() = %q, want %q", got, register.LoadModeTypesInfo)
+	}
+
+	analyzers, err := plugin.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("failed to build analyzers: %v", err)
+	}
+	if len(analyzers) != 1 {
+		t.Fatalf("got %d analyzers, want 1", len(analyzers))
+	}
+
+	// Use the requiredfield analyzer's test data.
+	testDataDir, err := filepath.Abs(filepath.Join("..", "testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testDataDir, analyzers[0], "fields_from_config")
+}
+
+func TestPlugin_rules(t *testing.T) {
+	// Same configuration as severity_from_config/requiredfield.rc.
+	plugin, err := New(map[string]any{
+		"rules": []any{
+			map[string]any{"type": "external.User", "fields": []any{"ID"}},
+			map[string]any{"type": "external.User", "fields": []any{"Name"}, "severity": "warning"},
+			map[string]any{
+				"type":     "external.User",
+				"fields":   []any{"Email"},
+				"severity": "info",
+				"category": "rollout",
+			},
+			map[string]any{"type": "external.Config", "fields": []any{"APIKey"}, "severity": "warning"},
+			map[string]any{"type": "external.Config", "fields": []any{"APIKey", "Token"}},
+			map[string]any{"type": "external.Server.TLS", "fields": []any{"Password"}},
+			map[string]any{"type": "severity_from_config.Settings", "fields": []any{"Timeout"}},
+			map[string]any{"type": "severity_from_config.Settings.Limits", "fields": []any{"Min"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to build plugin: %v", err)
+	}
+
+	analyzers, err := plugin.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("failed to build analyzers: %v", err)
+	}
+
+	testDataDir, err := filepath.Abs(filepath.Join("..", "testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testDataDir, analyzers[0], "severity_from_config")
+}
+
+func TestPlugin_promote(t *testing.T) {
+	plugin, err := New(map[string]any{"promote": true})
+	if err != nil {
+		t.Fatalf("failed to build plugin: %v", err)
+	}
+
+	analyzers, err := plugin.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("failed to build analyzers: %v", err)
+	}
+
+	if got := analyzers[0].Flags.Lookup("promote").Value.String(); got != "true" {
+		t.Errorf("-promote = %v, want true", got)
+	}
+}
+
+func TestPlugin_conversions(t *testing.T) {
+	plugin, err := New(map[string]any{"conversions": true})
+	if err != nil {
+		t.Fatalf("failed to build plugin: %v", err)
+	}
+
+	analyzers, err := plugin.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("failed to build analyzers: %v", err)
+	}
+
+	if got := analyzers[0].Flags.Lookup("conversions").Value.String(); got != "true" {
+		t.Errorf("-conversions = %v, want true", got)
+	}
+}
+
+func TestPlugin_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings any
+		wantErr  string
+	}{
+		{
+			name:     "unknown setting",
+			settings: map[string]any{"requierd": []any{"a.B.C"}},
+			wantErr:  `unknown field "requierd"`,
+		},
+		{
+			name:     "bad type",
+			settings: map[string]any{"required": "a.B.C"},
+			wantErr:  "decoding settings",
+		},
+		{
+			name:     "bad field",
+			settings: map[string]any{"required": []any{"a.B.C", "foo"}},
+			wantErr:  "required[1]: ",
+		},
+		{
+			name:     "bad exempt pattern",
+			settings: map[string]any{"exempt": []any{""}},
+			wantErr:  "exempt: ",
+		},
+		{
+			name:     "bad tag",
+			settings: map[string]any{"tags": []any{"validate"}},
+			wantErr:  "tags: ",
+		},
+		{
+			name: "bad rule",
+			settings: map[string]any{"rules": []any{
+				map[string]any{"type": "a.B", "fields": []any{"C"}, "severity": "fatal"},
+			}},
+			wantErr: "rules[0]: severity: ",
+		},
+		{
+			name:     "missing config",
+			settings: map[string]any{"config": "does-not-exist.rc"},
+			wantErr:  "config: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin, err := New(tt.settings)
+			if err == nil {
+				_, err = plugin.BuildAnalyzers()
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
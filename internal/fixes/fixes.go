@@ -0,0 +1,30 @@
+// Package fixes builds suggested fixes
+// shared by the analyzer and the requiredfield command.
+package fixes
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// MarkRequired returns an edit that marks the given field as required
+// by adding a "// required" comment at the end of its declaration.
+// Existing trailing comments are kept after it.
+func MarkRequired(field *ast.Field) analysis.TextEdit {
+	if field.Comment != nil {
+		// "// required // existing comment" is recognized
+		// as a required comment.
+		return analysis.TextEdit{
+			Pos:     field.Comment.Pos(),
+			End:     field.Comment.Pos(),
+			NewText: []byte("// required "),
+		}
+	}
+
+	return analysis.TextEdit{
+		Pos:     field.End(),
+		End:     field.End(),
+		NewText: []byte(" // required"),
+	}
+}
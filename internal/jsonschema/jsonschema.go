@@ -0,0 +1,45 @@
+// Package jsonschema holds JSON and JSON Schema helpers
+// shared by the analyzer and the requiredfield command.
+package jsonschema
+
+import (
+	"go/types"
+	"reflect"
+	"strings"
+)
+
+// FieldName returns the name of a struct field in JSON
+// given the field and its struct tag, the same as encoding/json.
+// It returns an empty name for embedded structs
+// whose fields are promoted to the outer object,
+// and reports false if the field is omitted from JSON.
+func FieldName(field *types.Var, tag string) (string, bool) {
+	value, hasTag := reflect.StructTag(tag).Lookup("json")
+	if value == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(value, ",")
+
+	if field.Embedded() && name == "" {
+		typ := field.Type()
+		if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+			typ = ptr.Elem()
+		}
+		if _, ok := typ.Underlying().(*types.Struct); ok {
+			return "", true
+		}
+	}
+
+	if !field.Exported() {
+		return "", false
+	}
+	if !hasTag || name == "" {
+		return field.Name(), true
+	}
+	return name, true
+}
+
+// EscapePointer escapes a name for use as a JSON pointer token.
+func EscapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
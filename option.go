@@ -0,0 +1,89 @@
+package requiredfield
+
+import (
+	"go/ast"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// visitOptions checks that a call to a variadic function
+// with a "requires-option" directive includes an option
+// from each required group, e.g.
+//
+//	//requiredfield:requires-option logger
+//	func New(opts ...Option) *Server
+//
+//	//requiredfield:option group=logger
+//	func WithLogger(*log.Logger) Option
+//
+// Options are recognized only if they're passed
+// as direct calls to functions with "option" directives.
+// Calls that pass options some other way, e.g. New(opts...),
+// are not checked.
+func (e *enforcer) visitOptions(call *ast.CallExpr) {
+	fn := typeutil.StaticCallee(e.Info, call)
+	if fn == nil {
+		return
+	}
+
+	var requires requiresOptions
+	if !e.ImportObjectFact(fn.Origin(), &requires) || call.Ellipsis.IsValid() {
+		return
+	}
+
+	sig := fn.Signature()
+	if !sig.Variadic() {
+		return
+	}
+
+	missing := slices.Clone(requires.Groups)
+	for _, arg := range call.Args[sig.Params().Len()-1:] {
+		group, ok := e.optionGroup(arg)
+		if !ok {
+			// We don't know where this option came from,
+			// so it may belong to any group.
+			return
+		}
+		missing = slices.DeleteFunc(missing, func(g string) bool {
+			return g == group
+		})
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	e.Report(analysis.Diagnostic{
+		Pos:      call.Lparen,
+		Category: categoryMissingOption,
+		Message:  "missing required options: " + strings.Join(missing, ", "),
+	})
+}
+
+// optionGroup returns the option group of an argument
+// to a function that requires options.
+// It returns an empty group if the argument is a call
+// to a function without an "option" directive,
+// and reports false if the argument is not a call.
+func (e *enforcer) optionGroup(arg ast.Expr) (group string, ok bool) {
+	call, ok := ast.Unparen(arg).(*ast.CallExpr)
+	if !ok {
+		return "", false
+	}
+	if tv, ok := e.Info.Types[call.Fun]; ok && tv.IsType() {
+		return "", false // conversion
+	}
+
+	fn := typeutil.StaticCallee(e.Info, call)
+	if fn == nil {
+		return "", false
+	}
+
+	var opt isOption
+	if e.ImportObjectFact(fn.Origin(), &opt) {
+		return opt.Group, true
+	}
+	return "", true
+}
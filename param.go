@@ -0,0 +1,92 @@
+package requiredfield
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// addParamFields adds fields required by a "param" directive, e.g.
+//
+//	//requiredfield:param params Name,URL
+//	func (c *Client) Do(ctx context.Context, params DoParams) error
+//
+// to unset if lit is passed directly as that parameter:
+//
+//	c.Do(ctx, DoParams{Name: "foo", URL: u})
+//
+// stack is the path to lit.
+// Fields the type already requires are reported as configured for the type.
+func (e *enforcer) addParamFields(unset map[string]diagClass, lit *ast.CompositeLit, stack []ast.Node) map[string]diagClass {
+	call, argIdx, ok := callArgument(stack)
+	if !ok {
+		return unset
+	}
+
+	fn := typeutil.StaticCallee(e.Info, call)
+	if fn == nil {
+		return unset
+	}
+
+	var requires requiresParamFields
+	if !e.ImportObjectFact(fn.Origin(), &requires) {
+		return unset
+	}
+
+	// For method expressions, e.g. (*Client).Do(c, ctx, params),
+	// the receiver is the first argument.
+	if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+		if s, ok := e.Info.Selections[sel]; ok && s.Kind() == types.MethodExpr {
+			argIdx--
+		}
+	}
+
+	for _, param := range requires.Params {
+		if param.Index != argIdx {
+			continue
+		}
+		for _, name := range param.Fields {
+			if _, ok := unset[name]; ok {
+				continue
+			}
+			if unset == nil {
+				unset = make(map[string]diagClass)
+			}
+			unset[name] = defaultClass
+		}
+	}
+	return unset
+}
+
+// callArgument reports whether the last node in stack
+// is passed directly as an argument to a function call,
+// possibly with its address taken, e.g. f(x, &T{...}).
+// It returns the call and the index of the argument.
+func callArgument(stack []ast.Node) (*ast.CallExpr, int, bool) {
+	idx := len(stack) - 2
+	for ; idx >= 0; idx-- {
+		if _, ok := stack[idx].(*ast.ParenExpr); ok {
+			continue
+		}
+		if unary, ok := stack[idx].(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			continue // &Params{...}
+		}
+		break
+	}
+	if idx < 0 {
+		return nil, 0, false
+	}
+
+	call, ok := stack[idx].(*ast.CallExpr)
+	if !ok || call.Ellipsis.IsValid() {
+		return nil, 0, false
+	}
+	for i, arg := range call.Args {
+		if arg == stack[idx+1] {
+			return call, i, true
+		}
+	}
+	return nil, 0, false // the function being called, e.g. T{...}.Method()
+}
@@ -0,0 +1,553 @@
+package requiredfield
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// partialReturn returns the function declaration
+// that returns lit directly as its first result, e.g.
+//
+//	func base() User {
+//		return User{Email: "x"}
+//	}
+//
+// It reports false if lit is not returned directly,
+// is returned from a function literal,
+// or the function's first result has a different type.
+func (e *enforcer) partialReturn(lit *ast.CompositeLit, stack []ast.Node) (*types.Func, bool) {
+	idx := len(stack) - 2
+	for ; idx >= 0; idx-- {
+		if _, ok := stack[idx].(*ast.ParenExpr); ok {
+			continue
+		}
+		if unary, ok := stack[idx].(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			continue // &User{...}
+		}
+		break
+	}
+	if idx < 0 {
+		return nil, false
+	}
+
+	ret, ok := stack[idx].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) == 0 || ret.Results[0] != stack[idx+1] {
+		return nil, false
+	}
+
+	for i := idx - 1; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncLit:
+			return nil, false
+
+		case *ast.FuncDecl:
+			fn, ok := e.Info.Defs[n.Name].(*types.Func)
+			if !ok || !e.returnsType(fn, e.Info.TypeOf(ret.Results[0])) {
+				return nil, false
+			}
+			return fn, true
+		}
+	}
+	return nil, false
+}
+
+// returnsType reports whether the first result of fn has the given type.
+func (e *enforcer) returnsType(fn *types.Func, typ types.Type) bool {
+	results := fn.Signature().Results()
+	return typ != nil && results.Len() > 0 && types.Identical(results.At(0).Type(), typ)
+}
+
+// addPartial records that the first result of fn
+// may be missing the given fields.
+// It reports whether any fields were added.
+func (e *enforcer) addPartial(fn *types.Func, fields []string) (changed bool) {
+	if e.partial == nil {
+		e.partial = make(map[*types.Func]map[string]struct{})
+	}
+	missing, ok := e.partial[fn]
+	if !ok {
+		missing = make(map[string]struct{})
+		e.partial[fn] = missing
+	}
+
+	for _, name := range fields {
+		if _, ok := missing[name]; !ok {
+			missing[name] = struct{}{}
+			changed = true
+		}
+	}
+	return changed
+}
+
+// partialFields returns the fields that may be missing
+// from the first result of fn.
+func (e *enforcer) partialFields(fn *types.Func) []string {
+	if missing, ok := e.partial[fn]; ok {
+		return slices.Collect(maps.Keys(missing))
+	}
+
+	// Facts for functions in this package are exported
+	// only after the package has been checked.
+	if fn.Pkg() == nil || fn.Pkg().Path() == e.PkgPath {
+		return nil
+	}
+	var fact partialResult
+	if e.ImportObjectFact(fn, &fact) {
+		return fact.List
+	}
+	return nil
+}
+
+// partialCall returns the function called by expr
+// and the required fields that may be missing from its first result,
+// and how to report them if they're never set.
+// It returns a nil call if expr is not a call to such a function.
+func (e *enforcer) partialCall(expr ast.Expr) (*ast.CallExpr, *types.Func, map[string]diagClass) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil, nil, nil
+	}
+	fn := typeutil.StaticCallee(e.Info, call)
+	if fn == nil {
+		return nil, nil, nil
+	}
+	fn = fn.Origin()
+
+	fields := e.partialFields(fn)
+	if len(fields) == 0 {
+		return nil, nil, nil
+	}
+
+	// Use the type of the call instead of the signature
+	// so that type arguments of generic functions are substituted.
+	typ := e.Info.TypeOf(call)
+	if tuple, ok := typ.(*types.Tuple); ok {
+		typ = tuple.At(0).Type()
+	}
+
+	required := e.requiredFields(derefAlias(typ), nil)
+	missing := make(map[string]diagClass, len(fields))
+	for _, name := range fields {
+		if class, ok := required[name]; ok {
+			missing[name] = class
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil, nil
+	}
+	return call, fn, missing
+}
+
+// checkPartial checks calls to functions
+// whose results may be missing required fields,
+// and exports facts for such functions declared in this package.
+//
+// Functions that return such results as-is
+// may be missing the same fields.
+func (e *enforcer) checkPartial(inspect *inspector.Inspector) {
+	var decls []*ast.FuncDecl
+	inspect.Preorder([]ast.Node{new(ast.FuncDecl)}, func(n ast.Node) {
+		if decl := n.(*ast.FuncDecl); decl.Body != nil {
+			decls = append(decls, decl)
+		}
+	})
+
+	// Propagate missing fields through functions
+	// until nothing changes, and only then report calls.
+	for changed := true; changed; {
+		changed = false
+		for _, decl := range decls {
+			flow := partialFlow{e: e, Decl: decl}
+			flow.Check()
+			changed = changed || flow.changed
+		}
+	}
+	for _, decl := range decls {
+		(&partialFlow{e: e, Decl: decl, Report: true}).Check()
+	}
+
+	for fn, missing := range e.partial {
+		e.ExportObjectFact(fn, &partialResult{
+			List: slices.Sorted(maps.Keys(missing)),
+		})
+	}
+}
+
+// partialValue is the result of a call to a function
+// whose results may be missing required fields,
+// assigned to a variable.
+type partialValue struct {
+	Var     *types.Var // nil if assigned to "_"
+	Call    *ast.CallExpr
+	Func    *types.Func
+	Missing map[string]diagClass
+
+	// Define is true if the variable is declared by the assignment,
+	// and therefore can't be used after the enclosing block.
+	Define bool
+}
+
+// partialFlow checks uses of results of functions
+// that may be missing required fields in a function declaration.
+type partialFlow struct {
+	e      *enforcer
+	Decl   *ast.FuncDecl // required
+	Report bool          // whether to report diagnostics
+
+	changed bool // whether new missing fields were recorded for Decl
+	handled map[*ast.CallExpr]struct{}
+}
+
+func (f *partialFlow) Check() {
+	f.handled = make(map[*ast.CallExpr]struct{})
+
+	// Track results assigned to variables first
+	// so that any remaining calls have their results used directly.
+	f.inspect(func(n ast.Node, inFuncLit bool) {
+		var list []ast.Stmt
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			list = n.List
+		case *ast.CaseClause:
+			list = n.Body
+		case *ast.CommClause:
+			list = n.Body
+		default:
+			return
+		}
+
+		for i, stmt := range list {
+			pv, ok := f.partialAssign(stmt)
+			if !ok {
+				continue
+			}
+			f.handled[pv.Call] = struct{}{}
+			if pv.Var != nil {
+				f.track(pv, list[i+1:], !inFuncLit)
+			}
+		}
+	})
+
+	f.inspect(func(n ast.Node, inFuncLit bool) {
+		switch n := n.(type) {
+		case *ast.ExprStmt:
+			// Results of calls made only for their side effects
+			// are never used.
+			if call, _, _ := f.e.partialCall(n.X); call != nil {
+				f.handled[call] = struct{}{}
+			}
+
+		case *ast.ReturnStmt:
+			if inFuncLit || len(n.Results) == 0 {
+				return
+			}
+			call, _, missing := f.e.partialCall(n.Results[0])
+			if call != nil && f.returnsAsIs(n.Results[0]) {
+				f.handled[call] = struct{}{}
+				f.propagate(missing)
+			}
+
+		case *ast.CallExpr:
+			if _, ok := f.handled[n]; ok {
+				return
+			}
+			if call, fn, missing := f.e.partialCall(n); call != nil {
+				f.report(call, fn, missing)
+			}
+		}
+	})
+}
+
+// inspect calls fn for each node in the body of the function,
+// reporting whether the node is inside a function literal.
+func (f *partialFlow) inspect(fn func(n ast.Node, inFuncLit bool)) {
+	var (
+		stack    []ast.Node
+		funcLits int
+	)
+	ast.Inspect(f.Decl.Body, func(n ast.Node) bool {
+		if n == nil {
+			if _, ok := stack[len(stack)-1].(*ast.FuncLit); ok {
+				funcLits--
+			}
+			stack = stack[:len(stack)-1]
+			return true
+		}
+
+		stack = append(stack, n)
+		if _, ok := n.(*ast.FuncLit); ok {
+			funcLits++
+		}
+		fn(n, funcLits > 0)
+		return true
+	})
+}
+
+// partialAssign reports whether stmt assigns the result of a call
+// to a function whose results may be missing required fields
+// to a variable, e.g.
+//
+//	u := base()
+//	var u = base()
+//	u, err := base()
+func (f *partialFlow) partialAssign(stmt ast.Stmt) (*partialValue, bool) {
+	var (
+		lhs, rhs ast.Expr
+		define   bool
+	)
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		if len(s.Rhs) != 1 || (s.Tok != token.ASSIGN && s.Tok != token.DEFINE) {
+			return nil, false
+		}
+		lhs, rhs = s.Lhs[0], s.Rhs[0]
+		define = s.Tok == token.DEFINE
+
+	case *ast.DeclStmt:
+		gen, ok := s.Decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR || len(gen.Specs) != 1 {
+			return nil, false
+		}
+		spec := gen.Specs[0].(*ast.ValueSpec)
+		if len(spec.Values) != 1 {
+			return nil, false
+		}
+		lhs, rhs = spec.Names[0], spec.Values[0]
+		define = true
+
+	default:
+		return nil, false
+	}
+
+	id, ok := ast.Unparen(lhs).(*ast.Ident)
+	if !ok {
+		// Assignments to fields, map elements, etc.
+		// are uses of the result.
+		return nil, false
+	}
+
+	call, fn, missing := f.e.partialCall(rhs)
+	if call == nil {
+		return nil, false
+	}
+
+	pv := partialValue{
+		Call:    call,
+		Func:    fn,
+		Missing: missing,
+		Define:  define,
+	}
+	if id.Name != "_" {
+		pv.Var, _ = f.e.Info.ObjectOf(id).(*types.Var)
+	}
+	return &pv, true
+}
+
+// track checks that the missing fields of a partial value
+// are set in the statements that follow its assignment
+// before the variable is used.
+//
+// propagate specifies whether the variable may be returned as-is
+// from the function being checked,
+// making the function's results partial too.
+func (f *partialFlow) track(pv *partialValue, rest []ast.Stmt, propagate bool) {
+	missing := maps.Clone(pv.Missing)
+	for _, stmt := range rest {
+		if f.uses(stmt, pv.Var, missing) {
+			if ret, ok := stmt.(*ast.ReturnStmt); ok && propagate && len(ret.Results) > 0 &&
+				f.isVarOrDeref(ret.Results[0], pv.Var) && f.returnsAsIs(ret.Results[0]) {
+				f.propagate(missing)
+				return
+			}
+
+			f.report(pv.Call, pv.Func, missing)
+			return
+		}
+
+		fields, reassigned := f.assigned(stmt, pv.Var)
+		if reassigned {
+			return
+		}
+		for name := range fields {
+			delete(missing, name)
+		}
+		if len(missing) == 0 {
+			return
+		}
+	}
+
+	// Variables declared outside the block
+	// may be used after it.
+	if !pv.Define {
+		f.report(pv.Call, pv.Func, missing)
+	}
+}
+
+// uses reports whether node uses v other than to set its fields
+// or to read fields that aren't missing.
+func (f *partialFlow) uses(node ast.Node, v *types.Var, missing map[string]diagClass) bool {
+	ignore := make(map[*ast.Ident]struct{})
+	var used bool
+	ast.Inspect(node, func(n ast.Node) bool {
+		if used {
+			return false
+		}
+
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Tok != token.ASSIGN && n.Tok != token.DEFINE {
+				// v.F += 1 reads the field.
+				break
+			}
+			for _, lhs := range n.Lhs {
+				switch lhs := ast.Unparen(lhs).(type) {
+				case *ast.SelectorExpr:
+					if f.isField(lhs) {
+						if id, ok := lhs.X.(*ast.Ident); ok {
+							ignore[id] = struct{}{}
+						}
+					}
+				case *ast.Ident:
+					ignore[lhs] = struct{}{}
+				}
+			}
+
+		case *ast.SelectorExpr:
+			if _, ok := missing[n.Sel.Name]; !ok && f.isField(n) {
+				if id, ok := n.X.(*ast.Ident); ok {
+					ignore[id] = struct{}{}
+				}
+			}
+
+		case *ast.Ident:
+			if _, ok := ignore[n]; !ok && f.e.Info.Uses[n] == v {
+				used = true
+			}
+		}
+		return true
+	})
+	return used
+}
+
+// assigned returns the fields of v that stmt sets on every path,
+// and reports whether stmt assigns a new value to v.
+func (f *partialFlow) assigned(stmt ast.Stmt, v *types.Var) (fields map[string]struct{}, reassigned bool) {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		if s.Tok != token.ASSIGN && s.Tok != token.DEFINE {
+			return nil, false
+		}
+
+		fields = make(map[string]struct{})
+		for _, lhs := range s.Lhs {
+			switch lhs := ast.Unparen(lhs).(type) {
+			case *ast.SelectorExpr:
+				if f.isVar(lhs.X, v) && f.isField(lhs) {
+					fields[lhs.Sel.Name] = struct{}{}
+				}
+			case *ast.Ident:
+				if f.isVar(lhs, v) {
+					return nil, true
+				}
+			}
+		}
+		return fields, false
+
+	case *ast.BlockStmt:
+		return f.assignedAll(s.List, v)
+
+	case *ast.IfStmt:
+		if s.Else == nil {
+			return nil, false
+		}
+
+		body, reassigned := f.assignedAll(s.Body.List, v)
+		if reassigned {
+			return nil, true
+		}
+		els, reassigned := f.assigned(s.Else, v)
+		if reassigned {
+			return nil, true
+		}
+
+		maps.DeleteFunc(body, func(name string, _ struct{}) bool {
+			_, ok := els[name]
+			return !ok
+		})
+		return body, false
+	}
+
+	// Fields set in loops and switches
+	// may not be set on every path.
+	return nil, false
+}
+
+func (f *partialFlow) assignedAll(list []ast.Stmt, v *types.Var) (map[string]struct{}, bool) {
+	fields := make(map[string]struct{})
+	for _, stmt := range list {
+		set, reassigned := f.assigned(stmt, v)
+		if reassigned {
+			return nil, true
+		}
+		maps.Copy(fields, set)
+	}
+	return fields, false
+}
+
+// returnsAsIs reports whether expr, the first result of a return statement
+// in the function being checked, has the type of the function's first result.
+func (f *partialFlow) returnsAsIs(expr ast.Expr) bool {
+	fn, ok := f.e.Info.Defs[f.Decl.Name].(*types.Func)
+	return ok && f.e.returnsType(fn, f.e.Info.TypeOf(expr))
+}
+
+// propagate records that the function being checked
+// may return results missing the given fields.
+func (f *partialFlow) propagate(missing map[string]diagClass) {
+	fn, ok := f.e.Info.Defs[f.Decl.Name].(*types.Func)
+	if !ok {
+		return
+	}
+	if f.e.addPartial(fn, slices.Collect(maps.Keys(missing))) {
+		f.changed = true
+	}
+}
+
+func (f *partialFlow) report(call *ast.CallExpr, fn *types.Func, missing map[string]diagClass) {
+	if !f.Report || len(missing) == 0 {
+		return
+	}
+
+	name := fn.Name()
+	if pkg := fn.Pkg(); pkg != nil && pkg.Path() != f.e.PkgPath {
+		name = pkg.Name() + "." + name
+	}
+	f.e.reportMissing(call.Lparen, missing, fmt.Sprintf("missing required fields after call to %v: ", name))
+}
+
+// isVar reports whether expr refers to v.
+func (f *partialFlow) isVar(expr ast.Expr, v *types.Var) bool {
+	id, ok := ast.Unparen(expr).(*ast.Ident)
+	return ok && f.e.Info.ObjectOf(id) == v
+}
+
+// isVarOrDeref reports whether expr refers to v or to the value it points to.
+func (f *partialFlow) isVarOrDeref(expr ast.Expr, v *types.Var) bool {
+	if star, ok := ast.Unparen(expr).(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	return f.isVar(expr, v)
+}
+
+// isField reports whether sel selects a field.
+func (f *partialFlow) isField(sel *ast.SelectorExpr) bool {
+	s, ok := f.e.Info.Selections[sel]
+	return ok && s.Kind() == types.FieldVal
+}
@@ -0,0 +1,304 @@
+package requiredfield
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"go.abhg.dev/requiredfield/internal/fixes"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// promoter reports optional fields of struct types declared in a package,
+// offering a fix to mark each as required.
+//
+// The fix also sets the field in all literals of the type in the package
+// so that the package remains free of diagnostics.
+// This is intended for editors (e.g. gopls)
+// where the diagnostic is presented as a code action on the field.
+type promoter struct {
+	Fset   *token.FileSet            // required
+	Info   *types.Info               // required
+	Pkg    *types.Package            // required
+	Report func(analysis.Diagnostic) // required
+	Config *requiredConfig
+}
+
+// promotableLit is a struct literal that may need to be updated
+// if a field of its type is made required.
+type promotableLit struct {
+	Lit  *ast.CompositeLit
+	Type *types.Named // type of the literal; may be an instantiation
+	File *ast.File
+}
+
+func (p *promoter) Promote(inspect *inspector.Inspector) {
+	// Literals of named struct types declared in this package.
+	lits := make(map[*types.TypeName][]promotableLit)
+	inspect.WithStack(_enforceNodeFilter, func(n ast.Node, push bool, stack []ast.Node) (proceed bool) {
+		if !push {
+			return true
+		}
+
+		lit := n.(*ast.CompositeLit)
+		named, ok := derefAlias(p.Info.TypeOf(lit)).(*types.Named)
+		if !ok || named.Obj().Pkg() != p.Pkg {
+			return true
+		}
+
+		// Unkeyed literals already set all fields,
+		// and the enforcer ignores literals returned with errors.
+		if len(lit.Elts) > 0 {
+			if _, ok := lit.Elts[0].(*ast.KeyValueExpr); !ok {
+				return true
+			}
+		}
+		if len(stack) > 1 && isReturnedWithNonNilError(stack) {
+			return true
+		}
+
+		file, _ := stack[0].(*ast.File)
+		obj := named.Origin().Obj()
+		lits[obj] = append(lits[obj], promotableLit{
+			Lit:  lit,
+			Type: named,
+			File: file,
+		})
+		return true
+	})
+
+	inspect.Preorder([]ast.Node{new(ast.TypeSpec)}, func(n ast.Node) {
+		spec := n.(*ast.TypeSpec)
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return
+		}
+		obj, ok := p.Info.Defs[spec.Name].(*types.TypeName)
+		if !ok || obj.Parent() != p.Pkg.Scope() {
+			// Only package-level types may be configured
+			// or used across files.
+			return
+		}
+
+		// Fields configured for all instantiations of the type.
+		var configured []string
+		typ, _ := obj.Type().Underlying().(*types.Struct)
+		for _, cf := range p.Config.configuredFields(p.Pkg.Path(), obj.Name()) {
+			if name, ok := cf.Rule.FieldName(typ, cf.Name); ok && cf.Type.typeArgs == "" {
+				configured = append(configured, name)
+			}
+		}
+
+		file := p.Fset.File(st.Pos())
+		for _, field := range st.Fields.List {
+			if _, ok := requiredComment(file, field); ok {
+				continue
+			}
+			if _, ok := p.Config.requiredTagOf(field); ok {
+				continue
+			}
+
+			idents := field.Names
+			if len(idents) == 0 {
+				if id := embeddedName(field.Type); id != nil {
+					idents = []*ast.Ident{id}
+				}
+			}
+
+			names := make([]string, 0, len(idents))
+			for _, id := range idents {
+				if id.Name != "_" && !slices.Contains(configured, id.Name) {
+					names = append(names, id.Name)
+				}
+			}
+			// Comments apply to all names in a field,
+			// so only offer the fix if it applies to all of them.
+			if len(names) == 0 || len(names) != len(idents) {
+				continue
+			}
+
+			p.Report(p.promote(field, names, lits[obj]))
+		}
+	})
+}
+
+// promote builds a diagnostic for an optional field
+// with a fix to mark it as required.
+// lits are literals of the field's type.
+//
+// The fix is not offered if any of the literals can't be updated
+// because that would introduce new diagnostics.
+func (p *promoter) promote(field *ast.Field, names []string, lits []promotableLit) analysis.Diagnostic {
+	nameList := strings.Join(names, ", ")
+	diag := analysis.Diagnostic{
+		Pos:      field.Pos(),
+		End:      field.End(),
+		Category: categoryPromote,
+		Message:  fmt.Sprintf("%v may be marked as required", nameList),
+	}
+
+	edits := []analysis.TextEdit{fixes.MarkRequired(field)}
+	for _, pl := range lits {
+		litEdits, ok := p.setFieldsEdits(pl, names)
+		if !ok {
+			return diag
+		}
+		edits = append(edits, litEdits...)
+	}
+
+	diag.SuggestedFixes = []analysis.SuggestedFix{
+		{
+			Message:   fmt.Sprintf("Mark %v as required", nameList),
+			TextEdits: edits,
+		},
+	}
+	return diag
+}
+
+// setFieldsEdits returns edits that set the given fields
+// to their zero values in a literal if it doesn't set them already.
+// It returns false if the zero value of a field
+// can't be written in the literal's file,
+// e.g. because the file doesn't import the package of its type.
+func (p *promoter) setFieldsEdits(pl promotableLit, names []string) ([]analysis.TextEdit, bool) {
+	missing := make([]string, 0, len(names))
+	for _, name := range names {
+		if !setsField(pl.Lit, name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil, true
+	}
+
+	st, ok := pl.Type.Underlying().(*types.Struct)
+	if !ok {
+		return nil, false
+	}
+
+	imported := p.importedNames(pl.File)
+	var unimported bool
+	qual := func(pkg *types.Package) string {
+		if pkg == p.Pkg {
+			return ""
+		}
+		name, ok := imported[pkg]
+		if !ok {
+			unimported = true
+		}
+		return name
+	}
+
+	elts := make([]string, 0, len(missing))
+	for _, name := range missing {
+		v := fieldByName(st, name)
+		if v == nil {
+			return nil, false
+		}
+		zero, ok := zeroValue(v.Type(), qual)
+		if !ok || unimported {
+			return nil, false
+		}
+		elts = append(elts, name+": "+zero)
+	}
+
+	// If the closing brace is on its own line,
+	// add each field on a new line before it:
+	//
+	//	User{
+	//		ID: "1",
+	//		Name: "",
+	//	}
+	last := pl.Lit.Lbrace
+	if n := len(pl.Lit.Elts); n > 0 {
+		last = pl.Lit.Elts[n-1].End()
+	}
+	file := p.Fset.File(pl.Lit.Pos())
+	if rbraceLine := file.Line(pl.Lit.Rbrace); rbraceLine > file.Line(last) {
+		pos := file.LineStart(rbraceLine)
+		text := strings.Join(elts, ",\n") + ",\n"
+		return []analysis.TextEdit{{Pos: pos, End: pos, NewText: []byte(text)}}, true
+	}
+
+	text := strings.Join(elts, ", ")
+	pos := pl.Lit.Lbrace + 1
+	if len(pl.Lit.Elts) > 0 {
+		pos = last
+		text = ", " + text
+	}
+	return []analysis.TextEdit{{Pos: pos, End: pos, NewText: []byte(text)}}, true
+}
+
+// importedNames returns the names under which
+// packages are imported in the given file.
+func (p *promoter) importedNames(file *ast.File) map[*types.Package]string {
+	names := make(map[*types.Package]string)
+	if file == nil {
+		return names
+	}
+
+	for _, imp := range file.Imports {
+		pkgName := p.Info.PkgNameOf(imp)
+		if pkgName == nil || pkgName.Name() == "_" {
+			continue
+		}
+
+		name := pkgName.Name()
+		if name == "." {
+			name = ""
+		}
+		names[pkgName.Imported()] = name
+	}
+	return names
+}
+
+// zeroValue returns an expression for the zero value of typ,
+// or false if there isn't one.
+func zeroValue(typ types.Type, qual types.Qualifier) (string, bool) {
+	if _, ok := types.Unalias(typ).(*types.TypeParam); ok {
+		return "*new(" + types.TypeString(typ, qual) + ")", true
+	}
+
+	var zero string
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			zero = "false"
+		case t.Info()&types.IsString != 0:
+			zero = `""`
+		case t.Info()&types.IsNumeric != 0:
+			zero = "0"
+		case t.Kind() == types.UnsafePointer:
+			zero = "nil"
+		default:
+			return "", false
+		}
+
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		zero = "nil"
+
+	case *types.Struct, *types.Array:
+		zero = types.TypeString(typ, qual) + "{}"
+
+	default:
+		return "", false
+	}
+	return zero, true
+}
+
+// setsField reports whether a keyed literal sets the given field.
+func setsField(lit *ast.CompositeLit, name string) bool {
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if id, ok := kv.Key.(*ast.Ident); ok && id.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,335 @@
+package requiredfield
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// RequiredFields is the result of the Analyzer for a package.
+//
+// It lists required fields of struct types declared in the package
+// and all configured fields,
+// and may be queried for required fields of any struct type
+// used in the package,
+// including types imported from other packages
+// and fields marked required by configuration.
+//
+// Other analyzers may access it by listing the requiredfield analyzer
+// in their Requires:
+//
+//	fields := pass.ResultOf[requiredfield.Analyzer].(*requiredfield.RequiredFields)
+//	if fields.IsRequired(typ, "Name") {
+//		// ...
+//	}
+type RequiredFields struct {
+	// Fields is a list of required fields
+	// in the order they were declared.
+	//
+	// Fields marked required with a comment are listed first,
+	// followed by fields configured for the same type.
+	// A field may be listed more than once
+	// if it's marked required in multiple ways.
+	Fields []RequiredField
+
+	// Configured lists all fields configured as required
+	// with -required, -config, or Options.Config,
+	// including fields of types declared in other packages
+	// and of types that the package doesn't use.
+	//
+	// Fields are ordered by type.
+	// They're listed with their configured names
+	// and don't have a position.
+	Configured []RequiredField
+
+	pkgPath    string
+	importFact func(types.Object, analysis.Fact) bool
+	config     *requiredConfig
+}
+
+// Required returns the names of required fields of the given struct type
+// as enforced in the analyzed package, sorted and without duplicates.
+//
+// typ may be a named struct type, an alias to one, or a pointer to one,
+// or an anonymous struct type.
+// Fields configured for anonymous structs nested inside named types
+// are not included because they depend on how the struct is reached.
+//
+// Configured fields limited to other packages are not included,
+// and exempt packages are not taken into account.
+// Required returns nil if typ is not a struct type
+// or if it has no required fields.
+func (r *RequiredFields) Required(typ types.Type) []string {
+	if r == nil || typ == nil {
+		return nil
+	}
+
+	var names []string
+	switch typ := derefAlias(typ).(type) {
+	case *types.Named:
+		st, ok := typ.Underlying().(*types.Struct)
+		if !ok {
+			return nil
+		}
+
+		var fact hasRequiredFields
+		if r.importFact(typ.Obj(), &fact) {
+			names = append(names, fact.List...)
+		}
+
+		for _, ts := range namedTypeSpecs(typ) {
+			for configured, rule := range r.config.fieldsOf(ts, r.pkgPath) {
+				if name, ok := rule.FieldName(st, configured); ok {
+					names = append(names, name)
+				}
+			}
+		}
+
+	case *types.Struct:
+		for i := range typ.NumFields() {
+			if r.IsRequiredField(typ.Field(i)) {
+				names = append(names, typ.Field(i).Name())
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// IsRequired reports whether the field with the given name
+// is a required field of the given struct type.
+// See Required for the types that are supported.
+func (r *RequiredFields) IsRequired(typ types.Type, name string) bool {
+	return slices.Contains(r.Required(typ), name)
+}
+
+// IsRequiredField reports whether a field of an anonymous struct type
+// is marked required with a "// required" comment.
+//
+// Use Required or IsRequired for fields of named struct types.
+func (r *RequiredFields) IsRequiredField(field *types.Var) bool {
+	if r == nil || field == nil {
+		return false
+	}
+	var fact isRequiredField
+	return r.importFact(field, &fact)
+}
+
+var _resultType = reflect.TypeFor[*RequiredFields]()
+
+// RequiredField is a single required field in a struct type.
+type RequiredField struct {
+	// Type is the struct type that owns the field
+	// in the form "package/path.Type".
+	//
+	// Fields of anonymous structs nested inside a named type
+	// are identified by the path to them,
+	// e.g. "package/path.Type.Outer.Inner".
+	// Fields configured for specific instantiations of a generic type
+	// include the type arguments, e.g. "package/path.Type[int]".
+	Type string
+
+	// Name is the name of the field.
+	Name string
+
+	// Pos is the position of the field declaration.
+	Pos token.Pos
+
+	// Description is the text following "required"
+	// in the field's comment, if any.
+	// For example, "// required: must be positive"
+	// has the description "must be positive".
+	Description string
+
+	// Origin reports where the field was configured as required,
+	// e.g. "-required" or "path/to/requiredfield.rc:3".
+	//
+	// For fields marked with a struct tag,
+	// this is the tag, e.g. `validate:"required"`.
+	//
+	// This is empty for fields marked with a "// required" comment.
+	Origin string
+}
+
+// lister builds the RequiredFields for a package.
+type lister struct {
+	Fset    *token.FileSet            // required
+	Info    *types.Info               // required
+	PkgPath string                    // required
+	Config  *requiredConfig           // required
+	Report  func(analysis.Diagnostic) // required
+}
+
+// List lists required fields of named struct types
+// declared at the top level of the given files,
+// and of anonymous structs nested inside them.
+//
+// Configured fields that don't exist in these types
+// are reported as configuration errors.
+// Other packages that use the types don't report them
+// so that each error is reported once.
+func (l *lister) List(files []*ast.File) *RequiredFields {
+	var result RequiredFields
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range decl.Specs {
+				spec := spec.(*ast.TypeSpec)
+				st, ok := spec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+
+				ts := typeSpec{packagePath: l.PkgPath, typeName: spec.Name.Name}
+				result.Fields = l.structType(result.Fields, ts, spec.Name.Pos(), st)
+			}
+		}
+	}
+
+	for _, cf := range l.Config.allConfiguredFields() {
+		result.Configured = append(result.Configured, RequiredField{
+			Type:   cf.Type.String(),
+			Name:   cf.Name,
+			Origin: cf.Origin,
+		})
+	}
+	return &result
+}
+
+// structType appends required fields of the given struct type to fields.
+// pos is the position of the name of the type or field that declares it.
+func (l *lister) structType(fields []RequiredField, ts typeSpec, pos token.Pos, st *ast.StructType) []RequiredField {
+	file := l.Fset.File(st.Pos())
+
+	positions := make(map[string]token.Pos)
+	var nested []*ast.Field
+	for _, field := range st.Fields.List {
+		idents := field.Names
+		if len(idents) == 0 {
+			// Embedded fields are named after their type.
+			if id := embeddedName(field.Type); id != nil {
+				idents = []*ast.Ident{id}
+			}
+		}
+
+		var description, origin string
+		comment, ok := requiredComment(file, field)
+		if ok {
+			description = requiredDescription(comment)
+		} else if tag, tagged := l.Config.requiredTagOf(field); tagged {
+			ok = true
+			origin = tag.String()
+		}
+
+		for _, id := range idents {
+			positions[id.Name] = id.Pos()
+			if !ok {
+				continue
+			}
+
+			fields = append(fields, RequiredField{
+				Type:        ts.String(),
+				Name:        id.Name,
+				Pos:         id.Pos(),
+				Description: description,
+				Origin:      origin,
+			})
+		}
+
+		if anonStruct(field.Type) != nil {
+			nested = append(nested, field)
+		}
+	}
+
+	typ, _ := l.Info.TypeOf(st).(*types.Struct)
+	missing := make(map[string]struct{})
+	for _, cf := range l.Config.configuredFields(ts.packagePath, ts.typeName) {
+		// Fields that don't exist are listed with their configured names.
+		name, ok := cf.Rule.FieldName(typ, cf.Name)
+		if !ok {
+			name = cf.Name
+			if _, reported := missing[name]; !reported {
+				missing[name] = struct{}{}
+				l.Report(analysis.Diagnostic{
+					Pos:      pos,
+					Category: categoryConfigError,
+					Message:  fmt.Sprintf("configured required field %v does not exist in %v", name, ts),
+				})
+			}
+		}
+
+		fields = append(fields, RequiredField{
+			Type:   cf.Type.String(),
+			Name:   name,
+			Pos:    positions[name],
+			Origin: cf.Origin,
+		})
+	}
+
+	for _, field := range nested {
+		for _, id := range field.Names {
+			fields = l.structType(fields, ts.nested(id.Name), id.Pos(), anonStruct(field.Type))
+		}
+	}
+
+	return fields
+}
+
+// anonStruct returns the anonymous struct type held in a field
+// with the given type, if any.
+// This looks through pointers and the elements of slices, arrays, and maps,
+// matching the literals that nestedTypeSpecs resolves.
+func anonStruct(expr ast.Expr) *ast.StructType {
+	for {
+		switch t := expr.(type) {
+		case *ast.StructType:
+			return t
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.ArrayType:
+			expr = t.Elt
+		case *ast.MapType:
+			expr = t.Value
+		case *ast.ParenExpr:
+			expr = t.X
+		default:
+			return nil
+		}
+	}
+}
+
+// embeddedName returns the identifier that names an embedded field
+// with the given type, or nil if it can't be determined.
+func embeddedName(expr ast.Expr) *ast.Ident {
+	for {
+		switch t := expr.(type) {
+		case *ast.Ident:
+			return t
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.SelectorExpr:
+			return t.Sel
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.ParenExpr:
+			expr = t.X
+		default:
+			return nil
+		}
+	}
+}
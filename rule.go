@@ -0,0 +1,314 @@
+package requiredfield
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// fieldRule holds options for a configured required field
+// that control where and how it is enforced.
+//
+// A nil fieldRule applies to all packages.
+type fieldRule struct {
+	// Packages is a list of patterns matching packages
+	// where the field is required.
+	// If empty, the field is required in all packages.
+	Packages []packagePattern
+
+	// Exclude is a list of patterns matching packages
+	// where the field is not required.
+	// This takes precedence over Packages.
+	Exclude []packagePattern
+
+	// Severity of diagnostics for this field if it's missing.
+	Severity severity
+
+	// Category of diagnostics for this field if it's missing.
+	// Defaults to categoryMissingRequired if empty.
+	Category string
+
+	// JSONName indicates that the field is identified by its name in JSON,
+	// e.g. for fields imported from a JSON Schema.
+	// See fieldRule.FieldName.
+	JSONName bool
+}
+
+// parseFieldRule parses a list of options in the form "key=value"
+// into a fieldRule.
+// It returns nil if there are no options.
+func parseFieldRule(opts []string) (*fieldRule, error) {
+	if len(opts) == 0 {
+		return nil, nil
+	}
+
+	var rule fieldRule
+	for _, opt := range opts {
+		key, value, ok := strings.Cut(opt, "=")
+		if !ok {
+			return nil, fmt.Errorf("option %q: expected key=value", opt)
+		}
+
+		var err error
+		switch key {
+		case "packages":
+			rule.Packages, err = appendPackagePatterns(rule.Packages, strings.Split(value, ","))
+		case "exclude":
+			rule.Exclude, err = appendPackagePatterns(rule.Exclude, strings.Split(value, ","))
+		case "severity":
+			rule.Severity, err = parseSeverity(value)
+		case "category":
+			rule.Category, err = parseCategory(value)
+		default:
+			err = errors.New("unknown option")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("option %q: %w", key, err)
+		}
+	}
+
+	return &rule, nil
+}
+
+// AppliesTo reports whether this rule applies to code
+// in the package with the given import path.
+//
+// External test packages (with a "_test" suffix)
+// are matched as the package they test.
+func (r *fieldRule) AppliesTo(pkgPath string) bool {
+	if r == nil {
+		return true
+	}
+
+	pkgPath = strings.TrimSuffix(pkgPath, "_test")
+	for _, p := range r.Exclude {
+		if p.Match(pkgPath) {
+			return false
+		}
+	}
+
+	if len(r.Packages) == 0 {
+		return true
+	}
+	for _, p := range r.Packages {
+		if p.Match(pkgPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Class returns the diagnostic class for fields with this rule.
+func (r *fieldRule) Class() diagClass {
+	if r == nil {
+		return defaultClass
+	}
+
+	class := diagClass{Severity: r.Severity, Category: r.Category}
+	if class.Category == "" {
+		class.Category = categoryMissingRequired
+	}
+	return class
+}
+
+// Diagnostic categories reported by the analyzer.
+const (
+	// categoryMissingRequired is the default category
+	// for literals that are missing required fields.
+	categoryMissingRequired = "missing-required"
+
+	// categoryConfigError is the category for problems
+	// with the configuration found during analysis,
+	// e.g. a configured field that does not exist.
+	categoryConfigError = "config-error"
+
+	// categoryConversion is the category for conversions
+	// that may not set required fields of the target type.
+	categoryConversion = "conversion"
+
+	// categoryZeroValue is the category for zero values
+	// of struct types with required fields
+	// created without a literal.
+	categoryZeroValue = "zero-value"
+
+	// categoryMissingOption is the category for calls
+	// that are missing required options.
+	categoryMissingOption = "missing-option"
+
+	// categoryMissingCall is the category for calls
+	// to terminal methods of builders
+	// that are missing calls to required methods.
+	categoryMissingCall = "missing-call"
+
+	// categoryPromote is the category for optional fields
+	// that may be promoted to required with a suggested fix.
+	categoryPromote = "promote"
+)
+
+// _categoryDescriptions describes each diagnostic category
+// for tools that present categories as rules, e.g. SARIF reports.
+var _categoryDescriptions = map[string]string{
+	categoryMissingRequired: "Struct literal is missing required fields.",
+	categoryConfigError:     "Field configured as required does not exist.",
+	categoryConversion:      "Conversion may not set required fields of the target type.",
+	categoryZeroValue:       "Zero value created without a literal is missing required fields.",
+	categoryMissingOption:   "Call is missing required options.",
+	categoryMissingCall:     "Builder is missing calls to required methods.",
+	categoryPromote:         "Optional field may be marked as required.",
+}
+
+// DiagnosticSeverity returns the severity of a diagnostic
+// reported by the analyzer: "error", "warning", or "info",
+// and its message without the severity.
+//
+// Analysis drivers don't have a notion of severity,
+// so the analyzer reports diagnostics below "error"
+// with the severity as a prefix of the message,
+// e.g. "warning: missing required fields: URL".
+// Suggestions to mark fields as required are always "info".
+func DiagnosticSeverity(diag analysis.Diagnostic) (level, message string) {
+	for _, s := range []severity{severityWarning, severityInfo} {
+		if rest, ok := strings.CutPrefix(diag.Message, s.String()+": "); ok {
+			return s.String(), rest
+		}
+	}
+	if diag.Category == categoryPromote {
+		return severityInfo.String(), diag.Message
+	}
+	return severityError.String(), diag.Message
+}
+
+// DiagnosticCategory returns the category of a diagnostic
+// reported by the analyzer, and a short description of the category.
+//
+// Diagnostics without a category,
+// and those in categories configured for required fields,
+// are described as struct literals missing required fields.
+func DiagnosticCategory(diag analysis.Diagnostic) (category, description string) {
+	category = cmp.Or(diag.Category, categoryMissingRequired)
+	description, ok := _categoryDescriptions[category]
+	if !ok {
+		description = _categoryDescriptions[categoryMissingRequired]
+	}
+	return category, description
+}
+
+// diagClass classifies diagnostics for missing required fields.
+type diagClass struct {
+	Severity severity
+	Category string
+}
+
+// defaultClass is the diagnostic class
+// for fields marked required without any options,
+// including fields marked with "// required" comments.
+var defaultClass = diagClass{
+	Severity: severityError,
+	Category: categoryMissingRequired,
+}
+
+// Compare orders diagnostic classes from most to least severe,
+// and then by category.
+func (c diagClass) Compare(other diagClass) int {
+	if c.Severity != other.Severity {
+		return cmp.Compare(c.Severity, other.Severity)
+	}
+	return cmp.Compare(c.Category, other.Category)
+}
+
+// severity is the severity of a diagnostic.
+// Lower values are more severe.
+type severity int
+
+const (
+	severityError severity = iota
+	severityWarning
+	severityInfo
+)
+
+var _severityNames = []string{
+	severityError:   "error",
+	severityWarning: "warning",
+	severityInfo:    "info",
+}
+
+func parseSeverity(s string) (severity, error) {
+	if i := slices.Index(_severityNames, s); i >= 0 {
+		return severity(i), nil
+	}
+	return severityError, fmt.Errorf("unknown severity %q: must be one of %v", s, strings.Join(_severityNames, ", "))
+}
+
+func (s severity) String() string {
+	if int(s) < len(_severityNames) {
+		return _severityNames[s]
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+func parseCategory(s string) (string, error) {
+	if s == "" {
+		return "", errors.New("empty category")
+	}
+	if strings.ContainsFunc(s, unicode.IsSpace) {
+		return "", fmt.Errorf("category %q must not contain spaces", s)
+	}
+	return s, nil
+}
+
+// packagePattern matches import paths of packages
+// similarly to the "go" command:
+// "..." matches any string, including the empty string,
+// and a trailing "/..." also matches the parent directory.
+//
+// For example, "example.com/foo/..." matches
+// "example.com/foo" and "example.com/foo/bar",
+// but not "example.com/foobar".
+type packagePattern struct {
+	text string
+	re   *regexp.Regexp
+}
+
+func parsePackagePattern(pattern string) (packagePattern, error) {
+	if pattern == "" {
+		return packagePattern{}, errors.New("empty package pattern")
+	}
+
+	expr := regexp.QuoteMeta(pattern)
+	if rest, ok := strings.CutSuffix(expr, `/\.\.\.`); ok {
+		expr = rest + `(/\.\.\.)?`
+	}
+	expr = strings.ReplaceAll(expr, `\.\.\.`, `.*`)
+
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return packagePattern{}, fmt.Errorf("bad package pattern %q: %w", pattern, err)
+	}
+	return packagePattern{text: pattern, re: re}, nil
+}
+
+func appendPackagePatterns(dst []packagePattern, patterns []string) ([]packagePattern, error) {
+	for _, text := range patterns {
+		p, err := parsePackagePattern(text)
+		if err != nil {
+			return nil, err
+		}
+		dst = append(dst, p)
+	}
+	return dst, nil
+}
+
+// Match reports whether the given import path matches this pattern.
+func (p packagePattern) Match(pkgPath string) bool {
+	return p.re.MatchString(pkgPath)
+}
+
+func (p packagePattern) String() string {
+	return p.text
+}
@@ -0,0 +1,294 @@
+package requiredfield
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestPackagePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{
+			pattern: "example.com/foo",
+			match:   []string{"example.com/foo"},
+			noMatch: []string{"example.com/foo/bar", "example.com/foobar", "example.com"},
+		},
+		{
+			pattern: "example.com/foo/...",
+			match:   []string{"example.com/foo", "example.com/foo/bar", "example.com/foo/bar/baz"},
+			noMatch: []string{"example.com/foobar", "example.com"},
+		},
+		{
+			pattern: "example.com/.../internal",
+			match:   []string{"example.com/foo/internal", "example.com/a/b/internal"},
+			noMatch: []string{"example.com/foo/internal/bar"},
+		},
+		{
+			pattern: "...",
+			match:   []string{"foo", "example.com/foo/bar"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			p, err := parsePackagePattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("parsePackagePattern() error = %v", err)
+			}
+
+			for _, path := range tt.match {
+				if !p.Match(path) {
+					t.Errorf("Match(%q) = false, want true", path)
+				}
+			}
+			for _, path := range tt.noMatch {
+				if p.Match(path) {
+					t.Errorf("Match(%q) = true, want false", path)
+				}
+			}
+		})
+	}
+}
+
+func TestFieldRule_AppliesTo(t *testing.T) {
+	tests := []struct {
+		name    string
+		give    []string // options
+		applies []string
+		skips   []string
+	}{
+		{
+			name:    "NoOptions",
+			applies: []string{"foo", "example.com/foo"},
+		},
+		{
+			name:    "Packages",
+			give:    []string{"packages=example.com/prod/...,example.com/lib"},
+			applies: []string{"example.com/prod", "example.com/prod/api", "example.com/lib", "example.com/lib_test"},
+			skips:   []string{"example.com/tools/migrate", "example.com/lib/sub"},
+		},
+		{
+			name:    "Exclude",
+			give:    []string{"exclude=example.com/tools/..."},
+			applies: []string{"example.com/prod"},
+			skips:   []string{"example.com/tools", "example.com/tools/migrate"},
+		},
+		{
+			name: "PackagesAndExclude",
+			give: []string{
+				"packages=example.com/...",
+				"exclude=example.com/tools/migrate",
+				"exclude=example.com/tools/backfill",
+			},
+			applies: []string{"example.com/prod", "example.com/tools"},
+			skips:   []string{"example.com/tools/migrate", "example.com/tools/backfill", "other.com/foo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := parseFieldRule(tt.give)
+			if err != nil {
+				t.Fatalf("parseFieldRule() error = %v", err)
+			}
+
+			for _, pkg := range tt.applies {
+				if !rule.AppliesTo(pkg) {
+					t.Errorf("AppliesTo(%q) = false, want true", pkg)
+				}
+			}
+			for _, pkg := range tt.skips {
+				if rule.AppliesTo(pkg) {
+					t.Errorf("AppliesTo(%q) = true, want false", pkg)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFieldRule_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		give    []string
+		wantErr string
+	}{
+		{
+			name:    "NotKeyValue",
+			give:    []string{"packages"},
+			wantErr: `option "packages": expected key=value`,
+		},
+		{
+			name:    "UnknownOption",
+			give:    []string{"scope=foo"},
+			wantErr: `option "scope": unknown option`,
+		},
+		{
+			name:    "UnknownSeverity",
+			give:    []string{"severity=fatal"},
+			wantErr: `option "severity": unknown severity "fatal": must be one of error, warning, info`,
+		},
+		{
+			name:    "EmptyCategory",
+			give:    []string{"category="},
+			wantErr: `option "category": empty category`,
+		},
+		{
+			name:    "EmptyPattern",
+			give:    []string{"packages=foo,,bar"},
+			wantErr: `option "packages": empty package pattern`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFieldRule(tt.give)
+			if err == nil {
+				t.Fatalf("parseFieldRule() error = nil, want error")
+			}
+
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseFieldRule() error = %q, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFieldRule_Class(t *testing.T) {
+	tests := []struct {
+		name string
+		give []string
+		want diagClass
+	}{
+		{
+			name: "Default",
+			want: defaultClass,
+		},
+		{
+			name: "Severity",
+			give: []string{"severity=warning"},
+			want: diagClass{Severity: severityWarning, Category: categoryMissingRequired},
+		},
+		{
+			name: "Category",
+			give: []string{"category=rollout"},
+			want: diagClass{Severity: severityError, Category: "rollout"},
+		},
+		{
+			name: "Both",
+			give: []string{"severity=info", "category=rollout"},
+			want: diagClass{Severity: severityInfo, Category: "rollout"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := parseFieldRule(tt.give)
+			if err != nil {
+				t.Fatalf("parseFieldRule() error = %v", err)
+			}
+
+			if got := rule.Class(); got != tt.want {
+				t.Errorf("Class() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiagnosticCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		give     string
+		wantCat  string
+		wantDesc string
+	}{
+		{
+			name:     "Default",
+			wantCat:  "missing-required",
+			wantDesc: "Struct literal is missing required fields.",
+		},
+		{
+			name:     "Configured",
+			give:     "rollout",
+			wantCat:  "rollout",
+			wantDesc: "Struct literal is missing required fields.",
+		},
+		{
+			name:     "MissingCall",
+			give:     categoryMissingCall,
+			wantCat:  "missing-call",
+			wantDesc: "Builder is missing calls to required methods.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cat, desc := DiagnosticCategory(analysis.Diagnostic{Category: tt.give})
+			if cat != tt.wantCat || desc != tt.wantDesc {
+				t.Errorf("DiagnosticCategory() = %q, %q, want %q, %q", cat, desc, tt.wantCat, tt.wantDesc)
+			}
+		})
+	}
+
+	// Every category reported by the analyzer is described.
+	for _, category := range []string{
+		categoryMissingRequired,
+		categoryConfigError,
+		categoryConversion,
+		categoryZeroValue,
+		categoryMissingOption,
+		categoryMissingCall,
+		categoryPromote,
+	} {
+		if _, ok := _categoryDescriptions[category]; !ok {
+			t.Errorf("category %q has no description", category)
+		}
+	}
+}
+
+func TestDiagnosticSeverity(t *testing.T) {
+	tests := []struct {
+		name      string
+		give      analysis.Diagnostic
+		wantLevel string
+		wantMsg   string
+	}{
+		{
+			name:      "Error",
+			give:      analysis.Diagnostic{Message: "missing required fields: ID"},
+			wantLevel: "error",
+			wantMsg:   "missing required fields: ID",
+		},
+		{
+			name:      "Warning",
+			give:      analysis.Diagnostic{Message: "warning: missing required fields: ID"},
+			wantLevel: "warning",
+			wantMsg:   "missing required fields: ID",
+		},
+		{
+			name:      "Info",
+			give:      analysis.Diagnostic{Message: "info: missing required fields: ID"},
+			wantLevel: "info",
+			wantMsg:   "missing required fields: ID",
+		},
+		{
+			name:      "Promote",
+			give:      analysis.Diagnostic{Message: "ID may be marked as required", Category: categoryPromote},
+			wantLevel: "info",
+			wantMsg:   "ID may be marked as required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, msg := DiagnosticSeverity(tt.give)
+			if level != tt.wantLevel || msg != tt.wantMsg {
+				t.Errorf("DiagnosticSeverity() = %q, %q, want %q, %q", level, msg, tt.wantLevel, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package requiredfield
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/types"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.abhg.dev/requiredfield/internal/jsonschema"
+)
+
+// FieldName returns the name of the field in st
+// that a field configured with this rule refers to.
+// It reports false if st has no such field.
+func (r *fieldRule) FieldName(st *types.Struct, name string) (string, bool) {
+	if r == nil || !r.JSONName {
+		return name, fieldByName(st, name) != nil
+	}
+	return jsonField(st, name)
+}
+
+// jsonField returns the name of the field in st
+// that encoding/json decodes the JSON property with the given name into.
+// Exact matches are preferred over case-insensitive ones.
+// Fields of embedded structs are not searched.
+func jsonField(st *types.Struct, name string) (string, bool) {
+	if st == nil {
+		return "", false
+	}
+
+	var folded string
+	for i := range st.NumFields() {
+		f := st.Field(i)
+		jsonName, ok := jsonschema.FieldName(f, st.Tag(i))
+		switch {
+		case !ok, jsonName == "":
+			continue
+		case jsonName == name:
+			return f.Name(), true
+		case folded == "" && strings.EqualFold(jsonName, name):
+			folded = f.Name()
+		}
+	}
+	return folded, folded != ""
+}
+
+// SchemaImport imports required fields from a JSON Schema
+// or OpenAPI document.
+//
+// Imports are listed under "schemas" in a JSON configuration file.
+type SchemaImport struct {
+	// File is the path to the document.
+	// Relative paths are resolved relative to the including file.
+	File string `json:"file"` // required
+
+	// Types maps schema names to Go types
+	// in the form "package/path.Type".
+	Types map[string]string `json:"types"` // required
+}
+
+// parseSchemaConfig parses the value of a "schema" line in an .rc file
+// in the form "FILE NAME=TYPE [NAME=TYPE ...]".
+func parseSchemaConfig(value string) (*SchemaImport, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return nil, errors.New("no file specified")
+	}
+
+	cfg := SchemaImport{
+		File:  fields[0],
+		Types: make(map[string]string, len(fields)-1),
+	}
+	for _, field := range fields[1:] {
+		name, typ, ok := strings.Cut(field, "=")
+		if !ok || name == "" || typ == "" {
+			return nil, fmt.Errorf("expected NAME=TYPE: %q", field)
+		}
+		cfg.Types[name] = typ
+	}
+	return &cfg, nil
+}
+
+// importSchema marks fields of Go types as required
+// based on the "required" lists of schemas in a JSON Schema
+// or OpenAPI document in the JSON format.
+// Relative paths are resolved the same as for include.
+//
+// Schema names are looked up under "components/schemas" (OpenAPI 3),
+// "definitions" (OpenAPI 2), and "$defs" (JSON Schema).
+// Names starting with "#" are JSON pointers into the document,
+// with "#" alone referring to the document itself.
+//
+// Required properties are matched against Go fields
+// by their names in JSON.
+func (c *requiredConfig) importSchema(cfg *SchemaImport, includeStack []string) error {
+	if cfg.File == "" {
+		return errors.New("no file specified")
+	}
+	if len(cfg.Types) == 0 {
+		return errors.New("no schemas specified")
+	}
+
+	path := resolveIncludePath(cfg.File, includeStack)
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var doc any
+	if err := json.Unmarshal(bs, &doc); err != nil {
+		return fmt.Errorf("%v:%w", path, err)
+	}
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(cfg.Types)) {
+		if err := c.importSchemaType(path, doc, name, cfg.Types[name]); err != nil {
+			errs = append(errs, fmt.Errorf("schema %q: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *requiredConfig) importSchemaType(path string, doc any, name, typ string) error {
+	ts, err := parseTypeSpec(typ)
+	if err != nil {
+		return fmt.Errorf(`expected "package/path.Type": %w`, err)
+	}
+
+	pointer, schema, ok := findSchema(doc, name)
+	if !ok {
+		return errors.New("not found")
+	}
+
+	props, err := schemaRequired(doc, schema, make(map[string]struct{}))
+	if err != nil {
+		return err
+	}
+
+	rule := &fieldRule{JSONName: true}
+	origin := path + "#" + pointer
+	for _, prop := range props {
+		c.addRequiredTo(ts, prop, rule, origin)
+	}
+	return nil
+}
+
+// findSchema finds the schema with the given name in a document,
+// returning the JSON pointer to it.
+func findSchema(doc any, name string) (pointer string, schema any, ok bool) {
+	if ptr, ok := strings.CutPrefix(name, "#"); ok {
+		schema, ok := resolvePointer(doc, ptr)
+		return ptr, schema, ok
+	}
+
+	name = jsonschema.EscapePointer(name)
+	for _, dir := range []string{"/components/schemas/", "/definitions/", "/$defs/"} {
+		if schema, ok := resolvePointer(doc, dir+name); ok {
+			return dir + name, schema, true
+		}
+	}
+	return "", nil, false
+}
+
+// schemaRequired returns the required properties of a schema,
+// including those of schemas it references with "$ref" or "allOf".
+//
+// seen holds JSON pointers of referenced schemas
+// to guard against reference cycles.
+func schemaRequired(doc, schema any, seen map[string]struct{}) ([]string, error) {
+	obj, ok := schema.(map[string]any)
+	if !ok {
+		return nil, errors.New("schema is not an object")
+	}
+
+	var required []string
+	switch list := obj["required"].(type) {
+	case nil:
+		// no required properties
+	case []any:
+		for i, v := range list {
+			prop, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("required[%d]: expected a string, got %v", i, v)
+			}
+			required = append(required, prop)
+		}
+	default:
+		// "required: true" on properties is from older drafts.
+		return nil, errors.New("required: expected a list of property names")
+	}
+
+	if ref, ok := obj["$ref"].(string); ok {
+		ptr, ok := strings.CutPrefix(ref, "#")
+		if !ok {
+			return nil, fmt.Errorf("$ref %q: only references inside the document are supported", ref)
+		}
+		if _, ok := seen[ptr]; !ok {
+			seen[ptr] = struct{}{}
+
+			target, ok := resolvePointer(doc, ptr)
+			if !ok {
+				return nil, fmt.Errorf("$ref %q: not found", ref)
+			}
+			props, err := schemaRequired(doc, target, seen)
+			if err != nil {
+				return nil, fmt.Errorf("$ref %q: %w", ref, err)
+			}
+			required = append(required, props...)
+		}
+	}
+
+	if all, ok := obj["allOf"].([]any); ok {
+		for i, sub := range all {
+			props, err := schemaRequired(doc, sub, seen)
+			if err != nil {
+				return nil, fmt.Errorf("allOf[%d]: %w", i, err)
+			}
+			required = append(required, props...)
+		}
+	}
+
+	// Keep the first occurrence of each property.
+	seenProps := make(map[string]struct{}, len(required))
+	return slices.DeleteFunc(required, func(prop string) bool {
+		_, dup := seenProps[prop]
+		seenProps[prop] = struct{}{}
+		return dup
+	}), nil
+}
+
+// resolvePointer resolves a JSON pointer (RFC 6901) in a document.
+// An empty pointer refers to the document itself.
+func resolvePointer(doc any, ptr string) (any, bool) {
+	if ptr == "" {
+		return doc, true
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, false
+	}
+
+	v := doc
+	for tok := range strings.SplitSeq(ptr[1:], "/") {
+		tok = strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+		switch node := v.(type) {
+		case map[string]any:
+			child, ok := node[tok]
+			if !ok {
+				return nil, false
+			}
+			v = child
+		case []any:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
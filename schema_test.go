@@ -0,0 +1,242 @@
+package requiredfield
+
+import (
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONField(t *testing.T) {
+	field := func(name string) *types.Var {
+		return types.NewField(0, nil, name, types.Typ[types.String], false)
+	}
+	st := types.NewStruct(
+		[]*types.Var{
+			field("ID"),
+			field("UserName"),
+			field("Email"),
+			field("Secret"),
+			field("private"),
+			field("Exact"),
+			field("EXACT"),
+		},
+		[]string{
+			`json:"id"`,
+			`json:"user_name,omitempty"`,
+			``,
+			`json:"-"`,
+			``,
+			`json:"exact_lower"`,
+			`json:"exact"`,
+		},
+	)
+
+	tests := []struct {
+		give   string
+		want   string
+		wantOK bool
+	}{
+		{give: "id", want: "ID", wantOK: true},
+		{give: "user_name", want: "UserName", wantOK: true},
+		{give: "email", want: "Email", wantOK: true},
+		{give: "Email", want: "Email", wantOK: true},
+		{give: "ID", want: "ID", wantOK: true},
+		{give: "exact", want: "EXACT", wantOK: true},
+		{give: "Secret"},
+		{give: "private"},
+		{give: "UserName"},
+		{give: "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			got, ok := jsonField(st, tt.give)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("jsonField(%q) = %q, %v, want %q, %v", tt.give, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRequiredConfig_importSchema(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"api/openapi.json": `{
+			"definitions": {
+				"a/b": {"required": ["x"]},
+				"Loop": {"$ref": "#/definitions/Loop", "required": ["y"]}
+			},
+			"$defs": {
+				"Item": {"required": ["id", "name"]}
+			}
+		}`,
+		// Schema paths are resolved relative to the including file.
+		"requiredfield.rc": joinLines(
+			"schema api/openapi.json Item=example.com/api.Item",
+			"schema api/openapi.json a/b=example.com/api.Slash Loop=example.com/api.Loop",
+		),
+	})
+
+	var c requiredConfig
+	if err := c.LoadFile(filepath.Join(dir, "requiredfield.rc")); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	want := map[typeSpec][]string{
+		{packagePath: "example.com/api", typeName: "Item"}:  {"id", "name"},
+		{packagePath: "example.com/api", typeName: "Slash"}: {"x"},
+		{packagePath: "example.com/api", typeName: "Loop"}:  {"y"},
+	}
+	if !reflect.DeepEqual(c.requiredFields, want) {
+		t.Errorf("requiredFields = %v, want %v", c.requiredFields, want)
+	}
+
+	for ts, rules := range c.fieldRules {
+		for _, rule := range rules {
+			if rule == nil || !rule.JSONName {
+				t.Errorf("%v: fields must be matched by JSON name", ts)
+			}
+		}
+	}
+
+	itemOrigin := c.fieldOrigins[typeSpec{packagePath: "example.com/api", typeName: "Item"}][0]
+	if !strings.HasSuffix(itemOrigin, filepath.Join("api", "openapi.json")+"#/$defs/Item") {
+		t.Errorf("origin = %q, want path to the schema", itemOrigin)
+	}
+}
+
+func TestRequiredConfig_importSchema_matchesJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"schema.json": `{"required": ["a", "b"]}`})
+	schemaPath := filepath.Join(dir, "schema.json")
+
+	var rc, js Config
+	if err := rc.rc.Parse(strings.NewReader("schema " + schemaPath + " #=pkg.T\n")); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := js.ParseJSON(strings.NewReader(`{"schemas": [{"file": "` + filepath.ToSlash(schemaPath) + `", "types": {"#": "pkg.T"}}]}`)); err != nil {
+		t.Fatalf("ParseJSON() error = %v", err)
+	}
+
+	var api Config
+	if err := api.ImportSchema(schemaPath, map[string]string{"#": "pkg.T"}); err != nil {
+		t.Fatalf("ImportSchema() error = %v", err)
+	}
+
+	for _, c := range []*Config{&js, &api} {
+		if !reflect.DeepEqual(c.rc.requiredFields, rc.rc.requiredFields) {
+			t.Errorf("requiredFields = %v, want %v", c.rc.requiredFields, rc.rc.requiredFields)
+		}
+	}
+}
+
+func TestRequiredConfig_importSchema_errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"schema.json": `{
+			"components": {
+				"schemas": {
+					"External": {"$ref": "other.json#/Foo"},
+					"Dangling": {"$ref": "#/components/schemas/Missing"},
+					"BadRequired": {"required": true},
+					"BadProperty": {"required": [42]},
+					"NotObject": "string",
+					"BadAllOf": {"allOf": [{"required": "x"}]}
+				}
+			}
+		}`,
+		"invalid.json": "{\n\"required\": [\n}",
+	})
+
+	tests := []struct {
+		name    string
+		give    string // .rc file contents
+		wantErr []string
+	}{
+		{
+			name:    "no file",
+			give:    "schema",
+			wantErr: []string{"1:schema: no file specified"},
+		},
+		{
+			name:    "no schemas",
+			give:    "schema schema.json",
+			wantErr: []string{"no schemas specified"},
+		},
+		{
+			name:    "bad mapping",
+			give:    "schema schema.json User",
+			wantErr: []string{`expected NAME=TYPE: "User"`},
+		},
+		{
+			name:    "bad type",
+			give:    "schema schema.json External=Foo",
+			wantErr: []string{`schema "External"`, "no package or type specified"},
+		},
+		{
+			name:    "missing file",
+			give:    "schema missing.json #=pkg.T",
+			wantErr: []string{"missing.json"},
+		},
+		{
+			name:    "invalid JSON",
+			give:    "schema invalid.json #=pkg.T",
+			wantErr: []string{"invalid.json:", "invalid character"},
+		},
+		{
+			name:    "not found",
+			give:    "schema schema.json Missing=pkg.T",
+			wantErr: []string{`schema "Missing": not found`},
+		},
+		{
+			name:    "external reference",
+			give:    "schema schema.json External=pkg.T",
+			wantErr: []string{`$ref "other.json#/Foo": only references inside the document are supported`},
+		},
+		{
+			name:    "dangling reference",
+			give:    "schema schema.json Dangling=pkg.T",
+			wantErr: []string{`$ref "#/components/schemas/Missing": not found`},
+		},
+		{
+			name:    "required is not a list",
+			give:    "schema schema.json BadRequired=pkg.T",
+			wantErr: []string{"required: expected a list of property names"},
+		},
+		{
+			name:    "property is not a string",
+			give:    "schema schema.json BadProperty=pkg.T",
+			wantErr: []string{"required[0]: expected a string, got 42"},
+		},
+		{
+			name:    "schema is not an object",
+			give:    "schema schema.json NotObject=pkg.T",
+			wantErr: []string{"schema is not an object"},
+		},
+		{
+			name:    "bad allOf",
+			give:    "schema schema.json BadAllOf=pkg.T",
+			wantErr: []string{"allOf[0]: required:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeFiles(t, dir, map[string]string{"requiredfield.rc": tt.give})
+			path := filepath.Join(dir, "requiredfield.rc")
+
+			var c requiredConfig
+			err := c.LoadFile(path)
+			if err == nil {
+				t.Fatalf("LoadFile() error = nil, want error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("LoadFile() error = %q, want to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
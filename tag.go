@@ -0,0 +1,113 @@
+package requiredfield
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// requiredTag is a struct tag that marks a field as required,
+// e.g. `validate:"required"`.
+type requiredTag struct {
+	Key   string // e.g. "validate"
+	Value string // e.g. "required"
+}
+
+// parseRequiredTag parses a tag specification in the form "key:value",
+// e.g. "validate:required".
+func parseRequiredTag(spec string) (requiredTag, error) {
+	key, value, ok := strings.Cut(spec, ":")
+	if !ok {
+		return requiredTag{}, fmt.Errorf(`expected "key:value": %q`, spec)
+	}
+
+	// Tag values may be quoted the same as they are in the source.
+	if uq, err := strconv.Unquote(value); err == nil {
+		value = uq
+	}
+
+	switch {
+	case key == "":
+		return requiredTag{}, errors.New("tag key is empty")
+	case strings.ContainsAny(key, " \t\":,"):
+		return requiredTag{}, fmt.Errorf("invalid tag key %q", key)
+	case value == "":
+		return requiredTag{}, errors.New("tag value is empty")
+	case strings.Contains(value, ","):
+		return requiredTag{}, fmt.Errorf("tag value %q must not contain ','", value)
+	}
+
+	return requiredTag{Key: key, Value: value}, nil
+}
+
+func (t requiredTag) String() string {
+	return t.Key + ":" + strconv.Quote(t.Value)
+}
+
+// Match reports whether the given struct tag
+// has this tag's key with a comma-separated list of values
+// that includes this tag's value.
+//
+// For example, `validate:"required"` matches
+// `validate:"required,email"` and `validate:"email,required"`.
+//
+// Values after "dive" or "keys" apply to elements of the field
+// rather than the field itself, so they're not considered.
+// For example, `validate:"dive,required"` does not match.
+func (t requiredTag) Match(tag reflect.StructTag) bool {
+	values, ok := tag.Lookup(t.Key)
+	if !ok {
+		return false
+	}
+
+	for v := range strings.SplitSeq(values, ",") {
+		switch v = strings.TrimSpace(v); v {
+		case t.Value:
+			return true
+		case "dive", "keys":
+			return false
+		}
+	}
+	return false
+}
+
+// addTags parses tag specifications in the form "key:value"
+// and adds them to the tags that mark fields as required.
+func (c *requiredConfig) addTags(specs []string) error {
+	var errs []error
+	for _, spec := range specs {
+		tag, err := parseRequiredTag(spec)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !slices.Contains(c.tags, tag) {
+			c.tags = append(c.tags, tag)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// requiredTagOf returns the configured tag
+// that marks the given field as required, if any.
+func (c *requiredConfig) requiredTagOf(field *ast.Field) (requiredTag, bool) {
+	if c == nil || len(c.tags) == 0 || field.Tag == nil {
+		return requiredTag{}, false
+	}
+
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return requiredTag{}, false
+	}
+
+	for _, t := range c.tags {
+		if t.Match(reflect.StructTag(tag)) {
+			return t, true
+		}
+	}
+	return requiredTag{}, false
+}
@@ -0,0 +1,132 @@
+package requiredfield
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseRequiredTag(t *testing.T) {
+	tests := []struct {
+		give string
+		want requiredTag
+	}{
+		{give: "validate:required", want: requiredTag{Key: "validate", Value: "required"}},
+		{give: "binding:required", want: requiredTag{Key: "binding", Value: "required"}},
+		{give: `validate:"required"`, want: requiredTag{Key: "validate", Value: "required"}},
+		{give: "required:true", want: requiredTag{Key: "required", Value: "true"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			got, err := parseRequiredTag(tt.give)
+			if err != nil {
+				t.Fatalf("parseRequiredTag() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("parseRequiredTag() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRequiredTag_errors(t *testing.T) {
+	tests := []struct {
+		give    string
+		wantErr string
+	}{
+		{give: "validate", wantErr: `expected "key:value"`},
+		{give: ":required", wantErr: "tag key is empty"},
+		{give: "validate:", wantErr: "tag value is empty"},
+		{give: `validate:""`, wantErr: "tag value is empty"},
+		{give: "my tag:required", wantErr: "invalid tag key"},
+		{give: "validate:required,email", wantErr: "must not contain ','"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			_, err := parseRequiredTag(tt.give)
+			if err == nil {
+				t.Fatalf("parseRequiredTag() error = nil, want error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseRequiredTag() error = %q, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequiredTag_Match(t *testing.T) {
+	tag := requiredTag{Key: "validate", Value: "required"}
+
+	tests := []struct {
+		give string
+		want bool
+	}{
+		{give: `validate:"required"`, want: true},
+		{give: `json:"name" validate:"required"`, want: true},
+		{give: `validate:"required,email"`, want: true},
+		{give: `validate:"email, required"`, want: true},
+		{give: `validate:"omitempty"`},
+		{give: `validate:"required,dive,required"`, want: true},
+		{give: `validate:"dive,required"`},
+		{give: `validate:"omitempty,keys,required,endkeys"`},
+		{give: `validate:"required_if=Name foo"`},
+		{give: `validate:""`},
+		{give: `binding:"required"`},
+		{give: `json:"required"`},
+		{give: ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			if got := tag.Match(reflect.StructTag(tt.give)); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.give, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequiredConfig_requiredTagOf(t *testing.T) {
+	var c requiredConfig
+	if err := c.Parse(strings.NewReader(joinLines(
+		"tag validate:required",
+		"tag binding:required validate:required",
+	))); err != nil {
+		t.Fatalf("failed to parse configuration: %v", err)
+	}
+
+	want := []requiredTag{
+		{Key: "validate", Value: "required"},
+		{Key: "binding", Value: "required"},
+	}
+	if !reflect.DeepEqual(c.tags, want) {
+		t.Errorf("tags = %v, want %v", c.tags, want)
+	}
+
+	field := func(tag string) *ast.Field {
+		f := &ast.Field{Type: ast.NewIdent("string")}
+		if tag != "" {
+			f.Tag = &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(tag)}
+		}
+		return f
+	}
+
+	if got, ok := c.requiredTagOf(field(`binding:"required"`)); !ok || got != want[1] {
+		t.Errorf("requiredTagOf(binding) = %v, %v, want %v, true", got, ok, want[1])
+	}
+	if _, ok := c.requiredTagOf(field(`json:"x"`)); ok {
+		t.Errorf("requiredTagOf(json) = true, want false")
+	}
+	if _, ok := c.requiredTagOf(field("")); ok {
+		t.Errorf("requiredTagOf(no tag) = true, want false")
+	}
+
+	var nilConfig *requiredConfig
+	if _, ok := nilConfig.requiredTagOf(field(`validate:"required"`)); ok {
+		t.Errorf("nil config: requiredTagOf() = true, want false")
+	}
+}
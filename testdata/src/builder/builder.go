@@ -0,0 +1,135 @@
+package builder
+
+import "fmt"
+
+type Request struct {
+	Method string
+	URL    string
+	Body   string
+}
+
+type RequestBuilder struct {
+	req Request
+}
+
+func NewRequest() *RequestBuilder {
+	return &RequestBuilder{}
+}
+
+func (b *RequestBuilder) Method(m string) *RequestBuilder {
+	b.req.Method = m
+	return b
+}
+
+func (b *RequestBuilder) URL(u string) *RequestBuilder {
+	b.req.URL = u
+	return b
+}
+
+func (b *RequestBuilder) Body(body string) *RequestBuilder {
+	b.req.Body = body
+	return b
+}
+
+// Build returns the request.
+//
+//requiredfield:requires-call Method URL
+func (b *RequestBuilder) Build() Request { // want Build:"requires-call<Method, URL>"
+	return b.req
+}
+
+func chains() {
+	fmt.Println(NewRequest().Method("GET").URL("/").Build())
+	fmt.Println(NewRequest().URL("/").Body("x").Method("POST").Build())
+	fmt.Println(NewRequest().Method("GET").Build())        // want "missing required calls before Build: URL"
+	fmt.Println(NewRequest().Body("x").Build())            // want "missing required calls before Build: Method, URL"
+	fmt.Println((&RequestBuilder{}).Method("GET").Build()) // want "missing required calls before Build: URL"
+	fmt.Println(NewRequest().Method("GET").URL("/").Build())
+}
+
+func variables() {
+	b := NewRequest().Method("GET")
+	b.URL("/")
+	fmt.Println(b.Build())
+
+	c := NewRequest()
+	c.Method("GET")
+	fmt.Println(c.Build()) // want "missing required calls before Build: URL"
+
+	d := NewRequest()
+	d = d.Method("GET")
+	fmt.Println(d.URL("/").Build())
+
+	// Calls after Build don't count.
+	e := NewRequest().Method("GET")
+	fmt.Println(e.Build()) // want "missing required calls before Build: URL"
+	e.URL("/")
+
+	var f RequestBuilder
+	fmt.Println(f.Build()) // want "missing required calls before Build: Method, URL"
+}
+
+func conditionals(cond bool, urls []string) {
+	// Calls that may not run before Build don't count.
+	b := NewRequest().Method("GET")
+	if cond {
+		b.URL("/")
+	}
+	fmt.Println(b.Build()) // want "missing required calls before Build: URL"
+
+	c := NewRequest().Method("GET")
+	for _, u := range urls {
+		c = c.URL(u)
+	}
+	fmt.Println(c.Build()) // want "missing required calls before Build: URL"
+
+	// Calls in enclosing blocks do.
+	d := NewRequest().Method("GET")
+	if cond {
+		d.URL("/")
+		fmt.Println(d.Build())
+	}
+
+	e := NewRequest()
+	e.Method("GET")
+	switch {
+	case cond:
+		e.URL("/")
+		fmt.Println(e.Build())
+	}
+}
+
+func escapes() {
+	// Builders passed to other functions may be configured there.
+	b := NewRequest()
+	configure(b)
+	fmt.Println(b.Build())
+}
+
+// Builders from elsewhere are not checked.
+func parameters(b *RequestBuilder) Request {
+	return b.Build()
+}
+
+var _global = NewRequest().Method("GET")
+
+func globals() Request {
+	return _global.Build()
+}
+
+func closures() func() Request {
+	b := NewRequest().Method("GET")
+	return func() Request {
+		return b.Build()
+	}
+}
+
+func configure(b *RequestBuilder) { b.Method("GET").URL("/") }
+
+//requiredfield:requires-call Method
+func notMethod() {} // want `invalid requiredfield:requires-call directive: function is not a method`
+
+type Other struct{}
+
+//requiredfield:requires-call Missing
+func (Other) Build() {} // want `invalid requiredfield:requires-call directive: unknown method "Missing"`
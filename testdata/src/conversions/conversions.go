@@ -0,0 +1,99 @@
+package conversions
+
+import (
+	"external"
+	"fmt"
+)
+
+type User struct { // want User:"required<Email, Name>"
+	Name  string // required
+	Email string // required
+	Age   int
+}
+
+// LegacyUser has the same fields as User
+// but doesn't require any of them.
+type LegacyUser struct {
+	Name  string
+	Email string
+	Age   int
+}
+
+// Account requires some of the fields of User.
+type Account struct { // want Account:"required<Name>"
+	Name  string // required
+	Email string
+	Age   int
+}
+
+// Member requires the same fields as User.
+type Member struct { // want Member:"required<Email, Name>"
+	Name  string // required
+	Email string // required
+	Age   int
+}
+
+func fromLegacy(u LegacyUser) {
+	fmt.Println(User(u)) // want "conversion from LegacyUser may not set required fields: Email, Name"
+}
+
+func fromPartial(a Account) {
+	fmt.Println(User(a)) // want "conversion from Account may not set required fields: Email"
+}
+
+func fromSameRequirements(m Member) {
+	fmt.Println(User(m))
+}
+
+func toOptional(u User) {
+	// Converting to a type without required fields is always fine.
+	fmt.Println(LegacyUser(u))
+}
+
+func sameType(u User) {
+	fmt.Println(User(u))
+}
+
+func pointers(u *LegacyUser) {
+	fmt.Println((*User)(u)) // want "conversion from LegacyUser may not set required fields: Email, Name"
+}
+
+func anonymous() {
+	u := struct {
+		Name  string
+		Email string
+		Age   int
+	}{Name: "x"}
+	fmt.Println(User(u)) // want `conversion from struct\{Name string; Email string; Age int\} may not set required fields: Email, Name`
+}
+
+func nonConversions(u User) {
+	// Function calls and conversions of non-struct types are ignored.
+	fmt.Println(string(rune(65)))
+	fmt.Println(identity(u))
+}
+
+func identity(u User) User { return u }
+
+// ExternalUser has the same fields as external.User.
+type ExternalUser struct {
+	ID    string
+	Name  string
+	Email string
+}
+
+func toExternal(u ExternalUser) {
+	fmt.Println(external.User(u)) // want "conversion from ExternalUser may not set required fields: ID" "warning: conversion from ExternalUser may not set required fields: Name"
+}
+
+func fromExternal(u external.User) {
+	fmt.Println(ExternalUser(u))
+}
+
+// Conversions returned with a non-nil error are allowed.
+func parseUser(u LegacyUser, err error) (User, error) {
+	if err != nil {
+		return User(u), err
+	}
+	return User(u), nil // want "conversion from LegacyUser may not set required fields: Email, Name"
+}
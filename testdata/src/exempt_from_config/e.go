@@ -0,0 +1,15 @@
+package e
+
+import (
+	"external"
+	"fmt"
+)
+
+type Request struct { // want Request:"required<Method>"
+	Method string // required
+}
+
+func _() {
+	fmt.Println(external.User{}) // want "missing required fields: ID"
+	fmt.Println(Request{})       // want "missing required fields: Method"
+}
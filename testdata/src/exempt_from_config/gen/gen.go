@@ -0,0 +1,20 @@
+package gen
+
+import (
+	"exempt_from_config"
+	"external"
+	"fmt"
+)
+
+// Required fields are not enforced in exempt packages,
+// but they're still found.
+
+type Response struct { // want Response:"required<Status>"
+	Status int // required
+}
+
+func _() {
+	fmt.Println(external.User{})
+	fmt.Println(e.Request{})
+	fmt.Println(Response{})
+}
@@ -12,3 +12,34 @@ type Config struct {
 	APIKey  string
 	Timeout int
 }
+
+type Server struct {
+	Name string
+
+	TLS struct {
+		CertFile string
+		KeyFile  string
+	}
+
+	Listeners []struct {
+		Addr    string
+		Network string
+
+		Limits struct {
+			MaxConns int
+		}
+	}
+
+	Routes map[string]*struct {
+		Handler string
+	}
+}
+
+type Page[T any] struct {
+	Items []T
+	Next  string
+
+	Meta struct {
+		Total int
+	}
+}
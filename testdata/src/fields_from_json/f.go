@@ -0,0 +1,17 @@
+package f
+
+import (
+	"external"
+	"fmt"
+)
+
+// External fields are marked from the requiredfield.json file.
+
+func useExternal() {
+	fmt.Println(external.User{}) // want "missing required fields: ID"
+	fmt.Println(external.User{ID: "123"})
+
+	fmt.Println(external.Config{})              // want "missing required fields: APIKey, Timeout"
+	fmt.Println(external.Config{APIKey: "key"}) // want "missing required fields: Timeout"
+	fmt.Println(external.Config{APIKey: "key", Timeout: 10})
+}
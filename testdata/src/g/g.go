@@ -125,3 +125,58 @@ func concreteNode() {
 	fmt.Println(ConcreteNode{})    // want "missing required fields: ID"
 	fmt.Println(ConcreteNode{ID: 1})
 }
+
+// Literals of type parameters whose constraint has a struct core type
+// must set the required fields of that type.
+func newContainer[T Container[int]]() T {
+	return T{} // want "missing required fields: Value"
+}
+
+func newContainerWithValue[T Container[int]]() T {
+	return T{Value: 1}
+}
+
+func newContainerPtr[T Container[int]]() *T {
+	return &T{Label: "x"} // want "missing required fields: Value"
+}
+
+type LegacyRecord struct {
+	Key   string
+	Value int
+}
+
+type Record struct { // want Record:"required<Key>"
+	Key   string // required
+	Value int
+}
+
+// If any type in the type set requires a field,
+// the literal must set it.
+func newRecord[T LegacyRecord | Record]() T {
+	return T{Value: 1} // want "missing required fields: Key"
+}
+
+func newRecordWithKey[T LegacyRecord | Record]() T {
+	return T{Key: "k"}
+}
+
+type RecordConstraint interface {
+	Record
+}
+
+func newRecordFromConstraint[T RecordConstraint]() T {
+	return T{} // want "missing required fields: Key"
+}
+
+// Constraints with multiple elements permit only the types in all of them.
+func newIntersectedRecord[T interface {
+	LegacyRecord | Record
+	LegacyRecord
+}]() T {
+	return T{}
+}
+
+// Constraints whose types have no required fields are fine.
+func newLegacyRecord[T LegacyRecord]() T {
+	return T{}
+}
@@ -0,0 +1,50 @@
+package gen
+
+import (
+	"external"
+	"fmt"
+	"g"
+)
+
+type UserAlias = external.User
+
+func specificInstantiation() {
+	fmt.Println(external.Page[external.User]{}) // want "missing required fields: Next"
+	fmt.Println(external.Page[external.User]{Next: "2"})
+	fmt.Println(external.Page[UserAlias]{}) // want "missing required fields: Next"
+
+	fmt.Println(external.Page[*external.User]{}) // want "missing required fields: Items"
+	fmt.Println(external.Page[*external.User]{Items: nil})
+
+	fmt.Println(external.Page[map[string]int]{}) // want "missing required fields: Items, Next"
+
+	// Other instantiations are not affected.
+	fmt.Println(external.Page[int]{})
+	fmt.Println(external.Page[[]external.User]{})
+}
+
+func nestedInInstantiation() {
+	fmt.Println(external.Page[string]{
+		Meta: struct{ Total int }{}, // want "missing required fields: Total"
+	})
+	fmt.Println(external.Page[int]{
+		Meta: struct{ Total int }{},
+	})
+}
+
+func mergedWithComments() {
+	// g.Pair marks both fields as required with comments.
+	fmt.Println(g.Pair[string, int]{})         // want "missing required fields: Key, Value"
+	fmt.Println(g.Pair[string, int]{Key: "x"}) // want "missing required fields: Value"
+}
+
+func allInstantiations() {
+	fmt.Println(g.Box[int]{})    // want "missing required fields: Size"
+	fmt.Println(g.Box[string]{}) // want "missing required fields: Size"
+	fmt.Println(g.Box[int]{Size: 1})
+}
+
+func genericCode[T any]() {
+	fmt.Println(external.Page[T]{})
+	fmt.Println(g.Box[T]{}) // want "missing required fields: Size"
+}
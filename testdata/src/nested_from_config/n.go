@@ -0,0 +1,72 @@
+package n
+
+import (
+	"external"
+	"fmt"
+)
+
+// Fields of anonymous structs nested inside external.Server
+// are marked from the requiredfield.rc file.
+
+type tlsConfig = struct {
+	CertFile string
+	KeyFile  string
+}
+
+func nestedField() {
+	fmt.Println(external.Server{ // want "missing required fields: Name"
+		TLS: struct {
+			CertFile string
+			KeyFile  string
+		}{}, // want "missing required fields: CertFile"
+	})
+
+	fmt.Println(external.Server{
+		Name: "foo",
+		TLS: struct {
+			CertFile string
+			KeyFile  string
+		}{CertFile: "cert.pem"},
+	})
+
+	// Unkeyed outer literal.
+	fmt.Println(external.Server{
+		"foo",
+		tlsConfig{KeyFile: "key.pem"}, // want "missing required fields: CertFile"
+		nil,
+		nil,
+	})
+}
+
+func nestedInCollection() {
+	fmt.Println(external.Server{
+		Name: "foo",
+		Listeners: []struct {
+			Addr    string
+			Network string
+			Limits  struct{ MaxConns int }
+		}{
+			{ // want "missing required fields: Addr"
+				Network: "tcp",
+				Limits:  struct{ MaxConns int }{MaxConns: 10},
+			},
+			{
+				Addr:   ":8080",
+				Limits: struct{ MaxConns int }{}, // want "missing required fields: MaxConns"
+			},
+		},
+		Routes: map[string]*struct{ Handler string }{
+			"/":    {},                          // want "missing required fields: Handler"
+			"/foo": &struct{ Handler string }{}, // want "missing required fields: Handler"
+			"/bar": {Handler: "bar"},
+		},
+	})
+}
+
+func notNested() {
+	// The same anonymous type outside of external.Server
+	// is not affected.
+	fmt.Println(tlsConfig{})
+	fmt.Println(struct{ Handler string }{})
+	fmt.Println(map[string]*struct{ Handler string }{"/": {}})
+}
@@ -0,0 +1,61 @@
+package options
+
+import (
+	"os"
+
+	"options/server"
+)
+
+func constructors() {
+	server.New(server.WithLogger(os.Stderr))
+	server.New(server.WithDefaults(), server.WithLogger(os.Stderr))
+	server.New()                                 // want "missing required options: logger"
+	server.New(server.WithDefaults())            // want "missing required options: logger"
+	server.New(server.WithPort(80))              // want "missing required options: logger"
+	server.Listen("tcp", server.WithLogger(nil)) // want "missing required options: port"
+	server.Listen("tcp")                         // want "missing required options: logger, port"
+	server.Listen("tcp", server.WithPort(80), server.WithLogger(nil))
+}
+
+func unknownOptions(opt server.Option, opts []server.Option) {
+	// Options that aren't passed as direct calls
+	// may belong to any group.
+	server.New(opt)
+	server.New(opts...)
+	server.New(func() server.Option { return opt }())
+}
+
+type Client struct{}
+
+type ClientOption func(*Client)
+
+//requiredfield:option group=timeout
+func WithTimeout(int) ClientOption { // want WithTimeout:"option<timeout>"
+	return nil
+}
+
+// Directives apply within the same package too.
+//
+//requiredfield:requires-option timeout
+func NewClient(opts ...ClientOption) *Client { // want NewClient:"requires-option<timeout>"
+	return &Client{}
+}
+
+func sameMachinery() {
+	NewClient(WithTimeout(1))
+	NewClient() // want "missing required options: timeout"
+}
+
+//requiredfield:option
+func missingGroup() ClientOption { // want `invalid requiredfield:option directive: expected group=NAME`
+	return nil
+}
+
+//requiredfield:option group=x
+func noResult() {} // want `invalid requiredfield:option directive: function does not return an option`
+
+//requiredfield:requires-option timeout
+func notVariadic(opts []ClientOption) {} // want `invalid requiredfield:requires-option directive: function is not variadic`
+
+//requiredfield:bogus
+func unknown() {} // want `invalid requiredfield:bogus directive: unknown directive`
@@ -0,0 +1,44 @@
+// Package server has a constructor that requires options.
+package server
+
+import "io"
+
+type Server struct {
+	logger io.Writer
+	port   int
+}
+
+type Option func(*Server)
+
+//requiredfield:option group=logger
+func WithLogger(w io.Writer) Option { // want WithLogger:"option<logger>"
+	return func(s *Server) { s.logger = w }
+}
+
+//requiredfield:option group=port
+func WithPort(port int) Option { // want WithPort:"option<port>"
+	return func(s *Server) { s.port = port }
+}
+
+// WithDefaults is an option that isn't in any group.
+func WithDefaults() Option {
+	return func(*Server) {}
+}
+
+// New builds a server.
+//
+//requiredfield:requires-option logger
+func New(opts ...Option) *Server { // want New:"requires-option<logger>"
+	var s Server
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return &s
+}
+
+// Listen requires a port as well as a logger.
+//
+//requiredfield:requires-option logger, port
+func Listen(network string, opts ...Option) *Server { // want Listen:"requires-option<logger, port>"
+	return New(opts...)
+}
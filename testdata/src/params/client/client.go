@@ -0,0 +1,33 @@
+package client
+
+type Client struct{}
+
+type DoParams struct { // want DoParams:"required<ID>"
+	Name    string
+	URL     string
+	Retries int
+	ID      string // required
+}
+
+// Do sends a request.
+//
+//requiredfield:param params Name,URL
+func (c *Client) Do(ctx any, params DoParams) error { // want Do:"param<params: Name, URL>"
+	return nil
+}
+
+// Get sends a GET request.
+//
+//requiredfield:param params URL
+func (c *Client) Get(params *DoParams) error { // want Get:"param<params: URL>"
+	return nil
+}
+
+// Copy copies between two locations.
+//
+//requiredfield:param dst URL
+//requiredfield:param src Name
+//requiredfield:param src URL
+func Copy(src, dst DoParams) error { // want Copy:"param<src: Name, URL; dst: URL>"
+	return nil
+}
@@ -0,0 +1,54 @@
+package params
+
+import "params/client"
+
+func calls(c *client.Client) {
+	_ = c.Do(nil, client.DoParams{ID: "1", Name: "foo", URL: "/"})
+	_ = c.Do(nil, client.DoParams{ID: "1", Name: "foo"}) // want "missing required fields: URL"
+	_ = c.Do(nil, client.DoParams{Retries: 3})           // want "missing required fields: ID, Name, URL"
+	_ = c.Do(nil, (client.DoParams{ID: "1", URL: "/"}))  // want "missing required fields: Name"
+	_ = c.Do(nil, client.DoParams{"foo", "/", 3, "1"})
+	_ = c.Get(&client.DoParams{ID: "1", URL: "/"})
+	_ = c.Get(&client.DoParams{ID: "1", Name: "foo"})      // want "missing required fields: URL"
+	_ = (*client.Client).Get(c, &client.DoParams{ID: "1"}) // want "missing required fields: URL"
+
+	_ = client.Copy(
+		client.DoParams{ID: "1", URL: "/a"}, // want "missing required fields: Name"
+		client.DoParams{ID: "2", Name: "b"}, // want "missing required fields: URL"
+	)
+}
+
+func indirect(c *client.Client) {
+	// Only literals passed directly are checked.
+	p := client.DoParams{ID: "1", Name: "foo"}
+	_ = c.Do(nil, p)
+}
+
+type Config struct {
+	Addr string
+	Port int
+}
+
+//requiredfield:param cfg Addr
+func Listen(cfg Config) {} // want Listen:"param<cfg: Addr>"
+
+func listen() {
+	Listen(Config{Addr: ":80"})
+	Listen(Config{Port: 80}) // want "missing required fields: Addr"
+	_ = Config{Port: 80}
+}
+
+//requiredfield:param cfg
+func noFields(cfg Config) {} // want `invalid requiredfield:param directive: expected PARAM FIELD,...`
+
+//requiredfield:param config Addr
+func unknownParam(cfg Config) {} // want `invalid requiredfield:param directive: no parameter named "config"`
+
+//requiredfield:param cfg Host
+func unknownField(cfg Config) {} // want `invalid requiredfield:param directive: field Host does not exist in params.Config`
+
+//requiredfield:param name Addr
+func notStruct(name string) {} // want `invalid requiredfield:param directive: parameter "name" is not a struct`
+
+//requiredfield:param cfgs Addr
+func variadic(cfgs ...Config) {} // want `invalid requiredfield:param directive: parameter "cfgs" is variadic`
@@ -0,0 +1,17 @@
+// Package helpers returns partially initialized values
+// for callers in other packages.
+package helpers
+
+type Request struct { // want Request:"required<Method, URL>"
+	Method string // required
+	URL    string // required
+	Body   []byte
+}
+
+func Get() Request { // want Get:"partial<URL>"
+	return Request{Method: "GET"}
+}
+
+func NewRequest() *Request { // want NewRequest:"partial<Method, URL>"
+	return &Request{}
+}
@@ -0,0 +1,162 @@
+package partial
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"partial/helpers"
+)
+
+type User struct { // want User:"required<Email, Name>"
+	Name  string // required
+	Email string // required
+	Age   int
+}
+
+// Helpers that return partial values are not reported.
+// Their callers must set the missing fields instead.
+func base() User { // want base:"partial<Name>"
+	return User{Email: "x"}
+}
+
+func basePtr() *User { // want basePtr:"partial<Email, Name>"
+	return &User{Age: 1}
+}
+
+func baseWithError() (User, error) { // want baseWithError:"partial<Name>"
+	if rand.Int()%2 == 0 {
+		return User{}, errors.New("fail") // ok: non-nil error
+	}
+	return User{Email: "x"}, nil
+}
+
+// Functions that return partial values as-is are partial too.
+func wrapper() User { // want wrapper:"partial<Name>"
+	return base()
+}
+
+func wrapperVar() User { // want wrapperVar:"partial<Name>"
+	u := basePtr()
+	u.Email = "y"
+	return *u
+}
+
+func setAfterCall() {
+	u := base()
+	u.Name = "alice"
+	fmt.Println(u)
+}
+
+func setOnEveryPath() {
+	u := base()
+	if rand.Int()%2 == 0 {
+		u.Name = "alice"
+	} else {
+		u.Name = "bob"
+	}
+	fmt.Println(u)
+}
+
+func setOnSomePaths() {
+	u := base() // want "missing required fields after call to base: Name"
+	if rand.Int()%2 == 0 {
+		u.Name = "alice"
+	}
+	fmt.Println(u)
+}
+
+func usedBeforeSet() {
+	u := base() // want "missing required fields after call to base: Name"
+	fmt.Println(u)
+	u.Name = "alice"
+}
+
+func readSetFields() {
+	u := base()
+	u.Name = u.Email
+	fmt.Println(u)
+}
+
+func readMissingFields() {
+	u := base() // want "missing required fields after call to base: Name"
+	u.Email = u.Name
+	fmt.Println(u)
+}
+
+func usedDirectly() {
+	fmt.Println(base())    // want "missing required fields after call to base: Name"
+	fmt.Println(wrapper()) // want "missing required fields after call to wrapper: Name"
+}
+
+func pointers() {
+	u := basePtr()
+	u.Name = "alice"
+	u.Email = "alice@example.com"
+	fmt.Println(u)
+
+	v := basePtr() // want "missing required fields after call to basePtr: Email"
+	v.Name = "bob"
+	fmt.Println(v)
+}
+
+func withError() error {
+	u, err := baseWithError()
+	if err != nil {
+		return err
+	}
+	u.Name = "alice"
+	fmt.Println(u)
+	return nil
+}
+
+func discarded() {
+	_ = base()
+	base()
+}
+
+func reassigned() {
+	u := base()
+	u = User{Name: "a", Email: "b"}
+	fmt.Println(u)
+}
+
+func neverUsed() {
+	u := base()
+	u.Age = 1
+}
+
+func assignedOutside() {
+	var u User
+	if rand.Int()%2 == 0 {
+		u = base() // want "missing required fields after call to base: Name"
+	}
+	fmt.Println(u)
+}
+
+func inFuncLit() func() User {
+	return func() User {
+		return base() // want "missing required fields after call to base: Name"
+	}
+}
+
+// Literals returned from function literals are reported as usual.
+func literalInFuncLit() func() User {
+	return func() User {
+		return User{Email: "x"} // want "missing required fields: Name"
+	}
+}
+
+// Functions that return other types are not partial.
+func returnsAny() any {
+	return User{Email: "x"} // want "missing required fields: Name"
+}
+
+func otherPackage() {
+	r := helpers.Get()
+	r.URL = "https://example.com"
+	fmt.Println(r)
+
+	fmt.Println(helpers.Get())        // want "missing required fields after call to helpers.Get: URL"
+	fmt.Println(helpers.NewRequest()) // want "missing required fields after call to helpers.NewRequest: Method, URL"
+}
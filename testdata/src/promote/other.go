@@ -0,0 +1,7 @@
+package promote
+
+// This file doesn't import "time",
+// so fields of that type can't be set here.
+var defaultUser = User{
+	ID: "4",
+}
@@ -0,0 +1,33 @@
+package promote
+
+import (
+	"errors"
+	"time"
+)
+
+type User struct { // want User:"required<ID>"
+	ID      string        // required
+	Name    string        // want "Name may be marked as required"
+	Age     int           // want "Age may be marked as required"
+	Timeout time.Duration // want "Timeout may be marked as required"
+	Created time.Time     // the creation time // want "Created may be marked as required"
+	Labels  []string      // want "Labels may be marked as required"
+}
+
+func NewUser(name string) User {
+	return User{ID: "1", Name: name}
+}
+
+func users() []User {
+	return []User{
+		{ID: "2", Age: 3},
+		{
+			ID:   "3",
+			Name: "bob",
+		},
+	}
+}
+
+func find(id string) (User, error) {
+	return User{}, errors.New("not found")
+}
@@ -0,0 +1,28 @@
+// Package result is queried by TestRequiredFields
+// through the analyzer's result.
+package result
+
+import (
+	"a"
+	"c"
+	"external"
+)
+
+type Local struct {
+	A string
+	B int
+}
+
+type Config = external.Config
+
+var (
+	oneRequired      a.OneRequired
+	requiredExported *a.RequiredExported
+	allOptional      a.AllOptional
+	embedded         c.Foo
+	anonymous        = (*c.Foo)(nil).Bar
+	user             external.User
+	alias            *Config
+	local            Local
+	notStruct        int
+)
@@ -0,0 +1,38 @@
+// Package api simulates types generated from an OpenAPI document.
+package api
+
+import "fmt"
+
+type Pet struct {
+	ID   int64   `json:"id"`
+	Name string  `json:"name"`
+	Tag  *string `json:"tag,omitempty"`
+}
+
+// NewPet has no "species" field
+// although the schema requires it.
+type NewPet struct { // want "configured required field species does not exist in schema_from_config.NewPet"
+	Name    string `json:"name"`
+	OwnerID string `json:"owner_id"`
+}
+
+// User has no json tags.
+// Properties are matched case-insensitively, same as encoding/json.
+type User struct {
+	Email string
+
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"-"`
+}
+
+func _() {
+	fmt.Println(Pet{})          // want "missing required fields: ID, Name"
+	fmt.Println(Pet{Name: "x"}) // want "missing required fields: ID"
+	fmt.Println(Pet{ID: 1, Name: "x"})
+
+	fmt.Println(NewPet{}) // want "missing required fields: Name, OwnerID"
+	fmt.Println(NewPet{Name: "x", OwnerID: "y"})
+
+	fmt.Println(User{Email: "a@example.com"}) // want "missing required fields: DisplayName"
+	fmt.Println(User{Email: "a@example.com", DisplayName: "A"})
+}
@@ -0,0 +1,13 @@
+package migrate
+
+import (
+	"external"
+	"fmt"
+)
+
+func _() {
+	fmt.Println(external.User{}) // want "missing required fields: ID"
+	fmt.Println(external.User{ID: "1"})
+
+	fmt.Println(external.Config{}) // want "missing required fields: APIKey"
+}
@@ -0,0 +1,16 @@
+package s
+
+import (
+	"external"
+	"fmt"
+)
+
+// Fields of external types are required only in some packages
+// per the requiredfield.rc file.
+
+func _() {
+	fmt.Println(external.User{}) // want "missing required fields: ID, Name"
+	fmt.Println(external.User{ID: "1", Name: "foo"})
+
+	fmt.Println(external.Config{})
+}
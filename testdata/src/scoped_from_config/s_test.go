@@ -0,0 +1,12 @@
+package s_test
+
+import (
+	"external"
+	"fmt"
+)
+
+// External test packages match the patterns of the package they test.
+
+func _() {
+	fmt.Println(external.User{}) // want "missing required fields: ID, Name"
+}
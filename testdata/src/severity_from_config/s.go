@@ -0,0 +1,40 @@
+package s
+
+import (
+	"external"
+	"fmt"
+)
+
+// Fields of external types are reported with different severities
+// per the requiredfield.rc file.
+
+func _() {
+	fmt.Println(external.User{})        // want "missing required fields: ID" "warning: missing required fields: Name" "info: missing required fields: Email"
+	fmt.Println(external.User{ID: "1"}) // want "warning: missing required fields: Name" "info: missing required fields: Email"
+	fmt.Println(external.User{ID: "1", Name: "foo", Email: "foo@example.com"})
+}
+
+func _() {
+	// Fields that don't exist are reported by the package that declares the type.
+	fmt.Println(external.Config{}) // want "missing required fields: APIKey"
+	fmt.Println(external.Server{
+		TLS: struct {
+			CertFile string
+			KeyFile  string
+		}{},
+	})
+}
+
+type Settings struct { // want "configured required field Timeout does not exist in severity_from_config.Settings"
+	Name string
+
+	Limits struct { // want "configured required field Min does not exist in severity_from_config.Settings.Limits"
+		Max int
+	}
+}
+
+func _() {
+	// Configuration errors are not reported where the type is used.
+	fmt.Println(Settings{})
+	fmt.Println(Settings{})
+}
@@ -0,0 +1,79 @@
+package strict
+
+import (
+	"external"
+	"fmt"
+)
+
+type User struct { // want User:"required<Name>"
+	Name  string // required
+	Email string
+}
+
+type Profile struct {
+	Bio string
+}
+
+type Users [2]User
+
+func makeSlices(n int) {
+	fmt.Println(make([]User, 3))    // want "zero-valued User elements are missing required fields: Name"
+	fmt.Println(make([]User, 3, 5)) // want "zero-valued User elements are missing required fields: Name"
+
+	// Empty slices don't have any zero values.
+	fmt.Println(make([]User, 0))
+	fmt.Println(make([]User, 0, 10))
+
+	// Lengths that aren't constant are not checked.
+	fmt.Println(make([]User, n))
+
+	// Pointers are nil, not zero-valued structs.
+	fmt.Println(make([]*User, 3))
+
+	// Types without required fields are fine.
+	fmt.Println(make([]Profile, 3))
+
+	// Maps don't have zero-valued elements.
+	fmt.Println(make(map[string]User, 3))
+
+	// Arrays in slices hold zero values too.
+	fmt.Println(make([][2]User, 1)) // want "zero-valued User elements are missing required fields: Name"
+}
+
+func growSlices(users []User) {
+	users = append(users, make([]User, 2)...) // want "zero-valued User elements are missing required fields: Name"
+	users = append(users, User{})             // want "missing required fields: Name"
+	users = append(users, User{Name: "x"})
+	fmt.Println(users)
+}
+
+func arrayVars() {
+	var users [3]User // want "zero-valued User elements are missing required fields: Name"
+	var a, b [1]User  // want "zero-valued User elements are missing required fields: Name" "zero-valued User elements are missing required fields: Name"
+	var named Users   // want "zero-valued User elements are missing required fields: Name"
+	var _ [3]User
+	var empty [0]User
+	fmt.Println(users, a, b, named, empty)
+
+	// Zero values of the struct types themselves are allowed.
+	var u User
+	fmt.Println(u)
+
+	// Variables with values are checked as literals.
+	var filled = [1]User{{Name: "x"}}
+	fmt.Println(filled)
+}
+
+func arrayLiterals() {
+	fmt.Println([2]User{{Name: "a"}})              // want "zero-valued User elements are missing required fields: Name"
+	fmt.Println([2]User{})                         // want "zero-valued User elements are missing required fields: Name"
+	fmt.Println([2]User{1: {Name: "b"}})           // want "zero-valued User elements are missing required fields: Name"
+	fmt.Println([2]User{{Name: "a"}, {Name: "b"}}) // all elements are set
+	fmt.Println([...]User{{Name: "a"}})            // length matches the elements
+	fmt.Println(Users{{Name: "a"}})                // want "zero-valued User elements are missing required fields: Name"
+	fmt.Println([]User{{Name: "a"}})               // slices have no unset elements
+}
+
+func configuredFields() {
+	fmt.Println(make([]external.User, 1)) // want "zero-valued external.User elements are missing required fields: ID"
+}
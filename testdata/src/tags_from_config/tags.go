@@ -0,0 +1,48 @@
+package tags
+
+import "fmt"
+
+type CreateUser struct { // want CreateUser:"required<Email, ID, Name, Role>"
+	ID    string // required
+	Name  string `json:"name" validate:"required"`
+	Email string `validate:"required,email"`
+	Role  string `binding:"required"`
+
+	// Only exact values in the list match.
+	Age      int    `validate:"omitempty,gte=0"`
+	Nickname string `validate:"required_without=Name"`
+
+	// Other tag keys are ignored.
+	Note string `json:"required"`
+
+	// Values after "dive" or "keys" apply to elements.
+	Tags   []string          `validate:"dive,required"`
+	Labels map[string]string `validate:"keys,required,endkeys"`
+
+	Address struct {
+		City string `validate:"required"` // want City:"required"
+		Zip  string
+	}
+}
+
+func _() {
+	fmt.Println(CreateUser{}) // want "missing required fields: Email, ID, Name, Role"
+	fmt.Println(CreateUser{   // want "missing required fields: Role"
+		ID:    "1",
+		Name:  "Alice",
+		Email: "alice@example.com",
+	})
+	fmt.Println(CreateUser{
+		ID:    "1",
+		Name:  "Alice",
+		Email: "alice@example.com",
+		Role:  "admin",
+	})
+}
+
+func _(u *CreateUser) {
+	u.Address = struct {
+		City string `validate:"required"` // want City:"required"
+		Zip  string
+	}{} // want "missing required fields: City"
+}
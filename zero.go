@@ -0,0 +1,119 @@
+package requiredfield
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+)
+
+// visitMake checks calls to make that create a slice
+// with zero-valued elements of a struct type with required fields, e.g.
+//
+//	make([]User, 3)
+//
+// Slices with a length of zero have no elements to check,
+// so make([]User, 0, n) is allowed.
+// Only constant lengths are checked.
+func (e *enforcer) visitMake(call *ast.CallExpr) {
+	if !e.isBuiltin(call.Fun, "make") || len(call.Args) < 2 {
+		return
+	}
+
+	slice, ok := types.Unalias(e.Info.TypeOf(call.Args[0])).Underlying().(*types.Slice)
+	if !ok {
+		return
+	}
+
+	tv, ok := e.Info.Types[call.Args[1]]
+	if !ok || tv.Value == nil {
+		return // not a constant
+	}
+	if n, ok := constant.Int64Val(constant.ToInt(tv.Value)); !ok || n == 0 {
+		return
+	}
+
+	e.reportZeroValues(call.Lparen, slice.Elem())
+}
+
+// visitArrayLit checks array literals that don't set every element,
+// leaving the rest zero-valued, e.g.
+//
+//	[3]User{{Name: "a"}}
+func (e *enforcer) visitArrayLit(lit *ast.CompositeLit) {
+	arr, ok := derefAlias(e.Info.TypeOf(lit)).Underlying().(*types.Array)
+	if !ok || int64(len(lit.Elts)) >= arr.Len() {
+		// Keys in array literals are unique and in range,
+		// so a literal with as many elements as the array sets all of them.
+		return
+	}
+
+	e.reportZeroValues(lit.Lbrace, arr.Elem())
+}
+
+// visitVarSpec checks variable declarations without values
+// whose type is an array, e.g.
+//
+//	var users [3]User
+func (e *enforcer) visitVarSpec(spec *ast.ValueSpec) {
+	if spec.Type == nil || len(spec.Values) > 0 {
+		return
+	}
+
+	typ := e.Info.TypeOf(spec.Type)
+	if typ == nil {
+		return
+	}
+	if _, ok := typ.Underlying().(*types.Array); !ok {
+		// Zero values of struct types are common
+		// as targets for decoding and the like.
+		return
+	}
+
+	for _, name := range spec.Names {
+		if name.Name != "_" {
+			e.reportZeroValues(name.Pos(), typ)
+		}
+	}
+}
+
+// reportZeroValues reports required fields
+// of zero values of the given type at pos.
+// Arrays are searched for elements of struct types.
+func (e *enforcer) reportZeroValues(pos token.Pos, typ types.Type) {
+	typ = types.Unalias(typ)
+	for {
+		arr, ok := typ.Underlying().(*types.Array)
+		if !ok {
+			break
+		}
+		if arr.Len() == 0 {
+			return
+		}
+		typ = types.Unalias(arr.Elem())
+	}
+
+	unset := e.requiredFields(typ, nil)
+	if len(unset) == 0 {
+		return
+	}
+	for name, class := range unset {
+		class.Category = categoryZeroValue
+		unset[name] = class
+	}
+
+	prefix := fmt.Sprintf("zero-valued %v elements are missing required fields: ",
+		types.TypeString(typ, e.qualifier))
+	e.reportMissing(pos, unset, prefix)
+}
+
+// isBuiltin reports whether expr refers to the builtin function with the given name.
+func (e *enforcer) isBuiltin(expr ast.Expr, name string) bool {
+	id, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	b, ok := e.Info.Uses[id].(*types.Builtin)
+	return ok && b.Name() == name
+}